@@ -1,12 +1,298 @@
 package config
 
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
 type Config struct {
 	ModuleName           string
 	AppType              string
 	Framework            string
 	UseDocker            bool
+	DockerRuntime        string
+	UseCompose           bool
 	UseAir               bool
-	UseMakefile          bool
+	UseVendor            bool
+	UseGitattributes     bool
+	Editor               string
+	License              string
+	LicenseAuthor        string
+	LicenseYear          string
+	SPDXHeaders          bool
+	TaskRunner           string
+	Deploy               string
+	CI                   string
+	LintPreset           string
+	GoVersion            string
+	ExampleResource      bool
+	UseScheduler         bool
+	UseSwaggo            bool
+	OpenAPISpec          string
+	Logger               string
 	ProjectDir           string
 	SelectedDependencies []string
+	Template             string
+	TemplateDir          string
+	TemplateVars         map[string]any
+}
+
+func (c *Config) ProjectName() string {
+	return path.Base(c.ModuleName)
+}
+
+// EnvPrefix turns the project name into an environment variable prefix,
+// e.g. my-tool becomes MY_TOOL.
+func (c *Config) EnvPrefix() string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, c.ProjectName())
+}
+
+// PackageName is the project name reduced to lower-case letters and
+// digits, so it is valid both as a Go package name and a protobuf package.
+func (c *Config) PackageName() string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return -1
+	}, c.ProjectName())
+	if name == "" || name[0] < 'a' {
+		name = "svc" + name
+	}
+	return name
+}
+
+// DNSName is the project name as a DNS label, the form Kubernetes resource
+// and Helm chart names need: lower case, with anything but letters and
+// digits turned into dashes.
+func (c *Config) DNSName() string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return '-'
+	}, c.ProjectName())
+	name = strings.Trim(name, "-")
+	if len(name) > 53 {
+		// Helm appends suffixes to release names, so leave it room.
+		name = strings.TrimRight(name[:53], "-")
+	}
+	if name == "" {
+		name = "app"
+	}
+	return name
+}
+
+// ProtoPackage names the protobuf package and directory under api/proto.
+func (c *Config) ProtoPackage() string {
+	return c.PackageName()
+}
+
+// IsWeb reports whether the project is an HTTP service built on one of the
+// web frameworks.
+func (c *Config) IsWeb() bool {
+	return c.AppType == "web"
+}
+
+// IsLibrary reports whether the project is an importable package without
+// a cmd/ entry point.
+func (c *Config) IsLibrary() bool {
+	return c.AppType == "library"
+}
+
+// HasAPIDocs reports whether the project gets an OpenAPI description and
+// a Swagger UI at /docs.
+func (c *Config) HasAPIDocs() bool {
+	return c.AppType == "web"
+}
+
+// SpecFile is the name of the OpenAPI document under api/. swag writes
+// Swagger 2.0 to swagger.yaml; hand-written specs are OpenAPI 3.
+func (c *Config) SpecFile() string {
+	if c.UseSwaggo {
+		return "swagger.yaml"
+	}
+	return "openapi.yaml"
+}
+
+// IsService reports whether the app type runs as a long-lived process
+// that gets the generated config package and .env.example.
+func (c *Config) IsService() bool {
+	switch c.AppType {
+	case "web", "graphql", "grpc", "worker":
+		return true
+	}
+	return false
+}
+
+// IsCLI reports whether the project is a command-line tool whose binaries
+// are published on release.
+func (c *Config) IsCLI() bool {
+	return c.AppType == "cli" || c.AppType == "cobra"
+}
+
+// UsesLinter reports whether golangci-lint is configured and pinned as a
+// go tool in the generated project.
+func (c *Config) UsesLinter() bool {
+	return c.LintPreset != "" && c.LintPreset != "none"
+}
+
+// UsesLicense reports whether the project gets a LICENSE file.
+func (c *Config) UsesLicense() bool {
+	return c.License != "" && c.License != "none"
+}
+
+// SPDXIdentifier is the SPDX-License-Identifier for c.License. Proprietary
+// code has no SPDX identifier, so it gets a LicenseRef of its own.
+func (c *Config) SPDXIdentifier() string {
+	if c.License == "proprietary" {
+		return "LicenseRef-Proprietary"
+	}
+	return c.License
+}
+
+// UsesDeploy reports whether deploy/ gets Kubernetes manifests or a Helm
+// chart.
+func (c *Config) UsesDeploy() bool {
+	return c.HasHealthEndpoint() && (c.Deploy == "kubernetes" || c.Deploy == "helm")
+}
+
+// ReadinessPath is the route readiness probes use. Workers keep serving
+// /health while they drain and report readiness on /ready; web servers stop
+// listening when they shut down, so /health serves both.
+func (c *Config) ReadinessPath() string {
+	if c.AppType == "worker" {
+		return "/ready"
+	}
+	return "/health"
+}
+
+// HasHealthEndpoint reports whether the app serves GET /health over HTTP,
+// which container health checks and probes can use.
+func (c *Config) HasHealthEndpoint() bool {
+	return c.AppType == "web" || c.AppType == "worker"
+}
+
+// UsesTaskRunner reports whether the project gets a Makefile, Taskfile.yml
+// or justfile.
+func (c *Config) UsesTaskRunner() bool {
+	switch c.TaskRunner {
+	case "make", "task", "just":
+		return true
+	}
+	return false
+}
+
+// TaskCommand is the command line that runs task with the chosen task
+// runner, or "" when the project has none.
+func (c *Config) TaskCommand(task string) string {
+	if !c.UsesTaskRunner() {
+		return ""
+	}
+	return c.TaskRunner + " " + task
+}
+
+func (c *Config) DefaultPort() int {
+	if c.AppType == "grpc" {
+		return 50051
+	}
+	return 8080
+}
+
+func (c *Config) HasDependency(importPath string) bool {
+	return slices.Contains(c.SelectedDependencies, importPath)
+}
+
+// Database returns the engine implied by the selected drivers: postgres,
+// mysql, sqlite, mongo, or "" when none was picked.
+func (c *Config) Database() string {
+	switch {
+	case c.HasDependency("gorm.io/driver/postgres"):
+		return "postgres"
+	case c.HasDependency("gorm.io/driver/mysql"):
+		return "mysql"
+	case c.HasDependency("gorm.io/driver/sqlite"):
+		return "sqlite"
+	case c.HasDependency("go.mongodb.org/mongo-driver/mongo"):
+		return "mongo"
+	}
+	return ""
+}
+
+// UsesSQL reports whether the selected database is reached through GORM.
+func (c *Config) UsesSQL() bool {
+	switch c.Database() {
+	case "postgres", "mysql", "sqlite":
+		return true
+	}
+	return false
+}
+
+// UsesStore reports whether the generated app connects to any backing
+// service at startup.
+func (c *Config) UsesStore() bool {
+	return c.Database() != "" || c.UsesRedis()
+}
+
+// UsesMigrations reports whether golang-migrate manages the SQL schema
+// under db/migrations.
+func (c *Config) UsesMigrations() bool {
+	return c.UsesSQL() && c.HasDependency("github.com/golang-migrate/migrate/v4")
+}
+
+// UsesSQLC reports whether queries under db/queries are compiled to Go
+// with sqlc.
+func (c *Config) UsesSQLC() bool {
+	return c.UsesSQL() && c.HasDependency("github.com/sqlc-dev/sqlc")
+}
+
+// HasCompose reports whether compose.yaml is generated: on request, and
+// always for services that need backing containers.
+func (c *Config) HasCompose() bool {
+	return c.UseCompose || (c.IsService() && len(c.BackingServices()) > 0)
+}
+
+func (c *Config) UsesRedis() bool {
+	return c.HasDependency("github.com/redis/go-redis/v9")
+}
+
+// BackingServices lists the containers the app needs next to it, named as
+// in the generated compose.yaml. SQLite runs in-process and has none.
+func (c *Config) BackingServices() []string {
+	var services []string
+	switch db := c.Database(); db {
+	case "postgres", "mysql", "mongo":
+		services = append(services, db)
+	}
+	if c.UsesRedis() {
+		services = append(services, "redis")
+	}
+	return services
+}
+
+// EnvLoader picks how the generated config package reads .env: viper or
+// godotenv when the user selected them, the standard library otherwise.
+func (c *Config) EnvLoader() string {
+	switch {
+	case c.HasDependency("github.com/spf13/viper"):
+		return "viper"
+	case c.HasDependency("github.com/joho/godotenv"):
+		return "godotenv"
+	}
+	return "stdlib"
 }
@@ -3,17 +3,14 @@ module github.com/SwanHtetAungPhyo/gostart
 go 1.24.3
 
 require (
-	github.com/PuerkitoBio/goquery v1.10.3
 	github.com/fatih/color v1.16.0
 	github.com/manifoldco/promptui v0.9.0
-	golang.org/x/sync v0.10.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/andybalholm/cascadia v1.3.3 // indirect
 	github.com/chzyer/readline v0.0.0-20180603132655-2972be24d48e // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
-	golang.org/x/net v0.39.0 // indirect
 	golang.org/x/sys v0.32.0 // indirect
 )
@@ -1,35 +1,194 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+	"github.com/SwanHtetAungPhyo/gostart/openapi"
+	"github.com/SwanHtetAungPhyo/gostart/runner"
 	scaffolder2 "github.com/SwanHtetAungPhyo/gostart/scaffolder"
+	"github.com/SwanHtetAungPhyo/gostart/templatepack"
+	"github.com/SwanHtetAungPhyo/gostart/templates"
 	"github.com/SwanHtetAungPhyo/gostart/wizzard"
-	"os"
 
 	"github.com/fatih/color"
 )
 
 func main() {
-	wizard := wizzard.NewWizard()
-	config, err := wizard.Run()
-	if err != nil {
+	if err := run(os.Args[1:]); err != nil {
 		color.Red("Error: %v", err)
 		os.Exit(1)
 	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return newProject(nil)
+	}
+
+	switch args[0] {
+	case "new":
+		return newProject(args[1:])
+	case "templates":
+		return templatesCommand(args[1:])
+	case "generate":
+		return generateCommand(args[1:])
+	case "help", "-h", "--help":
+		printUsage()
+		return nil
+	default:
+		printUsage()
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func printUsage() {
+	fmt.Println(`Usage:
+  gpm                                 start the interactive scaffolder
+  gpm new [--template <source>]       scaffold a project, optionally from a template pack
+  gpm new --from-openapi <file>       scaffold a web service from an OpenAPI 3 document
+  gpm generate openapi [--spec <file>]
+                                      regenerate models, handlers and routes in the
+                                      current project (default spec: api/openapi.yaml)
+  gpm templates update                refresh cached template packs
+
+Template sources:
+  git+file:///path/to/repo@v1.2.0
+  git+https://github.com/acme/kit.git@main`)
+}
+
+func newProject(args []string) error {
+	flags := flag.NewFlagSet("new", flag.ContinueOnError)
+	templateSource := flags.String("template", "", "template pack source (git+<url>[@ref])")
+	fromOpenAPI := flags.String("from-openapi", "", "OpenAPI 3 document to generate a web service from")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *templateSource != "" && *fromOpenAPI != "" {
+		return fmt.Errorf("--template and --from-openapi cannot be combined")
+	}
+
+	wizard := wizzard.NewWizard()
+	if *fromOpenAPI != "" {
+		// Fail before the questions rather than after them.
+		if _, err := openapi.Load(*fromOpenAPI); err != nil {
+			return err
+		}
+		wizard.OpenAPISpec = *fromOpenAPI
+	}
+	var (
+		config *config.Config
+		err    error
+	)
+	if *templateSource != "" {
+		pack, fetchErr := fetchTemplatePack(*templateSource)
+		if fetchErr != nil {
+			return fetchErr
+		}
+		config, err = wizard.RunTemplate(pack)
+	} else {
+		config, err = wizard.Run()
+	}
+	if err != nil {
+		return err
+	}
 
 	scaffolder := scaffolder2.NewScaffolder(config)
 	if err := scaffolder.CreateProject(); err != nil {
-		color.Red("Error creating project: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("creating project: %w", err)
 	}
 
 	color.Green("\n✅ Project '%s' created successfully!", config.ProjectDir)
 	color.Cyan("📁 Next steps:")
 	color.Yellow("   cd %s\n", config.ProjectDir)
-	if config.UseAir {
-		color.Red("Please try to fix the air.toml setting to get the hot reload. Because, air init generate the default setting")
-		color.Yellow("In this version, please run with the makefile command , \n make build")
-		color.Blue("   air ")
-	} else {
+	if dev := config.TaskCommand("dev"); config.UseAir && dev != "" {
+		color.Blue("   %s", dev)
+	} else if config.UseAir {
+		// Without a task runner there is no dev target to wrap it.
+		color.Blue("   %s", templates.AirCommand)
+	} else if config.IsLibrary() {
+		color.Green("   go test ./...")
+	} else if config.TemplateDir == "" {
 		color.Green("   go run ./cmd")
 	}
+	if config.AppType == "grpc" {
+		color.Green("   go run ./cmd/client -name Gopher")
+	}
+	if config.UseCompose {
+		color.Blue("   docker compose up --build")
+		color.Blue("   docker compose --profile dev up app-dev")
+	} else if config.HasCompose() {
+		// compose.yaml only holds the backing services the app connects to.
+		color.Blue("   docker compose up -d")
+	}
+	return nil
+}
+
+func fetchTemplatePack(raw string) (*templatepack.Pack, error) {
+	src, err := templatepack.ParseSource(raw)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := templatepack.NewCache(runner.NewExecRunner())
+	if err != nil {
+		return nil, err
+	}
+
+	color.Cyan("📥 Fetching template %s...", src)
+	return cache.Fetch(src)
+}
+
+func generateCommand(args []string) error {
+	if len(args) == 0 || args[0] != "openapi" {
+		printUsage()
+		return fmt.Errorf("usage: gpm generate openapi [--spec <file>]")
+	}
+
+	flags := flag.NewFlagSet("generate openapi", flag.ContinueOnError)
+	spec := flags.String("spec", "api/openapi.yaml", "OpenAPI 3 document to generate from")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	config, err := scaffolder2.ProjectConfig(".")
+	if err != nil {
+		return err
+	}
+	scaffolder := scaffolder2.NewScaffolder(config)
+	if err := scaffolder.GenerateOpenAPI(*spec, filepath.ToSlash(*spec)); err != nil {
+		return err
+	}
+
+	router, err := os.ReadFile(filepath.Join("internal", "handler", "router.go"))
+	if err == nil && !strings.Contains(string(router), "RegisterAPI(") {
+		color.Yellow("💡 Mount the generated routes in NewRouter: RegisterAPI(r, NewAPIServer())")
+	}
+	return nil
+}
+
+func templatesCommand(args []string) error {
+	if len(args) == 0 || args[0] != "update" {
+		printUsage()
+		return fmt.Errorf("usage: gpm templates update")
+	}
+
+	cache, err := templatepack.NewCache(runner.NewExecRunner())
+	if err != nil {
+		return err
+	}
+	updated, err := cache.Update()
+	for _, url := range updated {
+		color.Green("✓ Updated %s", url)
+	}
+	if err != nil {
+		return err
+	}
+	if len(updated) == 0 {
+		color.Yellow("No cached templates to update")
+	}
+	return nil
 }
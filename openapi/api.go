@@ -0,0 +1,1076 @@
+package openapi
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// API is the view of a document the stub templates render: the endpoints
+// to route and the types in internal/model they exchange.
+type API struct {
+	Source    string
+	Endpoints []*Endpoint
+	Types     []*Type
+	Patterns  []*Pattern
+	// Skipped lists operations left to hand-written routes.
+	Skipped []string
+
+	ModelImports   []string
+	HandlerImports []string
+	// HandlerUsesModel is set when the handler code refers to
+	// internal/model.
+	HandlerUsesModel bool
+	// NeedsRequireFields is set when a model checks required JSON keys.
+	NeedsRequireFields bool
+}
+
+type Endpoint struct {
+	Name      string
+	Method    string
+	Path      string
+	ColonPath string
+	BracePath string
+	Summary   string
+	// Params is the request struct in internal/model, nil when the
+	// operation takes no parameters or body.
+	Params *Type
+	// Result is the success body's Go type as seen from the handler
+	// package; empty when the response has no JSON body.
+	Result string
+	Zero   string
+	Status int
+}
+
+type Type struct {
+	Name string
+	Doc  string
+	// Kind is struct, enum, alias (a defined type), typealias (an alias
+	// of another component) or params.
+	Kind       string
+	Underlying string
+	Fields     []*Field
+	Required   []string
+	Values     []*EnumValue
+	// Checks validate the receiver v of alias types.
+	Checks []string
+	Params []*Param
+	Body   *Body
+}
+
+// HasChecks reports whether Validate has anything to do.
+func (t *Type) HasChecks() bool {
+	if len(t.Checks) > 0 {
+		return true
+	}
+	for _, f := range t.Fields {
+		if len(f.Checks) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+type Field struct {
+	Name   string
+	Type   string
+	Tag    string
+	Doc    string
+	Checks []string
+}
+
+type EnumValue struct {
+	Name  string
+	Value string
+}
+
+type Pattern struct {
+	Var  string
+	Expr string
+}
+
+type Param struct {
+	Name     string
+	Key      string
+	In       string
+	Field    string
+	Required bool
+	Kind     string
+	List     bool
+	Pointer  bool
+	Explode  bool
+
+	schema *Schema
+}
+
+type Body struct {
+	Required bool
+	JSON     bool
+}
+
+// goType is a Go type expression built from a schema.
+type goType struct {
+	name  string // a generated type in internal/model
+	prim  string // a predeclared or library type
+	elem  *goType
+	isMap bool
+	kind  string
+}
+
+func (t *goType) expr(qual string) string {
+	switch {
+	case t.name != "":
+		return qual + t.name
+	case t.elem != nil && t.isMap:
+		return "map[string]" + t.elem.expr(qual)
+	case t.elem != nil:
+		return "[]" + t.elem.expr(qual)
+	}
+	return t.prim
+}
+
+func (t *goType) nilable() bool {
+	switch t.kind {
+	case "slice", "map", "any", "bytes":
+		return true
+	}
+	return false
+}
+
+type builder struct {
+	doc        *Document
+	api        *API
+	declared   map[string]bool
+	components map[string]string
+	patterns   map[string]*Pattern
+}
+
+var methods = []string{"GET", "PUT", "POST", "DELETE", "PATCH", "HEAD", "OPTIONS"}
+
+// Build maps every operation in doc to an endpoint. source is the spec
+// path recorded in the generated files' headers.
+func Build(doc *Document, source string) (*API, error) {
+	b := &builder{
+		doc:        doc,
+		api:        &API{Source: source},
+		declared:   map[string]bool{},
+		components: map[string]string{},
+		patterns:   map[string]*Pattern{},
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	used, err := usedSchemas(doc, paths)
+	if err != nil {
+		return nil, err
+	}
+	names := sortedKeys(used)
+	for _, name := range names {
+		goName := goName(name)
+		if err := b.declare(goName); err != nil {
+			return nil, err
+		}
+		b.components[name] = goName
+	}
+	for _, name := range names {
+		if err := b.defineComponent(b.components[name], doc.Components.Schemas[name]); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+	}
+
+	seen := map[string]string{}
+	for _, path := range paths {
+		item := doc.Paths[path]
+		for _, method := range methods {
+			op := item.operation(method)
+			if op == nil {
+				continue
+			}
+			if reason := doc.skipReason(method, path, op); reason != "" {
+				b.api.Skipped = append(b.api.Skipped, fmt.Sprintf("%s %s (%s)", method, path, reason))
+				continue
+			}
+			endpoint, err := b.endpoint(method, path, item, op)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			if other, ok := seen[endpoint.Name]; ok {
+				return nil, fmt.Errorf("%s %s and %s both map to %s; set distinct operationIds", method, path, other, endpoint.Name)
+			}
+			seen[endpoint.Name] = method + " " + path
+			b.api.Endpoints = append(b.api.Endpoints, endpoint)
+		}
+	}
+
+	b.resolveImports()
+	return b.api, nil
+}
+
+// usedSchemas collects the component schemas the generated operations
+// refer to, directly or through other schemas. Schemas only used by
+// skipped operations are left out, since their models are hand-written.
+func usedSchemas(doc *Document, paths []string) (map[string]bool, error) {
+	used := map[string]bool{}
+	var walk func(s *Schema) error
+	walk = func(s *Schema) error {
+		if s == nil {
+			return nil
+		}
+		if s.Ref != "" {
+			name, err := refName(s.Ref, "schemas")
+			if err != nil {
+				return err
+			}
+			target, ok := doc.Components.Schemas[name]
+			if !ok {
+				return fmt.Errorf("unresolved reference %s", s.Ref)
+			}
+			if used[name] {
+				return nil
+			}
+			used[name] = true
+			return walk(target)
+		}
+		children := []*Schema{s.Items}
+		for _, prop := range s.Properties {
+			children = append(children, prop.Schema)
+		}
+		if s.AdditionalProperties != nil {
+			children = append(children, s.AdditionalProperties.Schema)
+		}
+		children = append(children, s.AllOf...)
+		children = append(children, s.OneOf...)
+		children = append(children, s.AnyOf...)
+		for _, child := range children {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	walkContent := func(content map[string]*MediaType) error {
+		for _, media := range content {
+			if media != nil {
+				if err := walk(media.Schema); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, path := range paths {
+		item := doc.Paths[path]
+		for _, method := range methods {
+			op := item.operation(method)
+			if op == nil || doc.skipReason(method, path, op) != "" {
+				continue
+			}
+			for _, p := range append(append([]*Parameter{}, item.Parameters...), op.Parameters...) {
+				param, err := doc.parameter(p)
+				if err != nil {
+					return nil, err
+				}
+				if err := walk(param.Schema); err != nil {
+					return nil, err
+				}
+			}
+			if op.RequestBody != nil {
+				body, err := doc.requestBody(op.RequestBody)
+				if err != nil {
+					return nil, err
+				}
+				if err := walkContent(body.Content); err != nil {
+					return nil, err
+				}
+			}
+			for _, r := range op.Responses {
+				response, err := doc.response(r)
+				if err != nil {
+					return nil, err
+				}
+				if err := walkContent(response.Content); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return used, nil
+}
+
+func (p *PathItem) operation(method string) *Operation {
+	switch method {
+	case "GET":
+		return p.Get
+	case "PUT":
+		return p.Put
+	case "POST":
+		return p.Post
+	case "DELETE":
+		return p.Delete
+	case "PATCH":
+		return p.Patch
+	case "HEAD":
+		return p.Head
+	case "OPTIONS":
+		return p.Options
+	}
+	return nil
+}
+
+// skipReason explains why an operation is not generated: the scaffolded
+// router already serves /, /health and /docs, x-gpm-manual marks
+// operations with hand-written handlers, and the generated handlers only
+// write JSON bodies.
+func (d *Document) skipReason(method, path string, op *Operation) string {
+	switch {
+	case op.Manual:
+		return "x-gpm-manual"
+	case method == "GET" && (path == "/" || path == "/health"):
+		return "served by NewRouter"
+	case path == "/docs" || strings.HasPrefix(path, "/docs/"):
+		return "served by NewRouter"
+	}
+	// Unresolved references are reported when the endpoint is built.
+	if chosen, _ := successResponse(op); chosen != nil {
+		if resp, err := d.response(chosen); err == nil && len(resp.Content) > 0 {
+			if _, ok := jsonMedia(resp.Content); !ok {
+				return "responds with " + strings.Join(sortedKeys(mediaTypes(resp.Content)), ", ") + "; only JSON responses are generated"
+			}
+		}
+	}
+	return ""
+}
+
+func (b *builder) declare(name string) error {
+	if b.declared[name] {
+		return fmt.Errorf("generated type name %s is used more than once; rename a schema or operationId", name)
+	}
+	b.declared[name] = true
+	return nil
+}
+
+func (b *builder) defineComponent(name string, s *Schema) error {
+	if s.Ref != "" {
+		t, err := b.typeOf(s, name)
+		if err != nil {
+			return err
+		}
+		b.api.Types = append(b.api.Types, &Type{Name: name, Doc: oneLine(s.Description), Kind: "typealias", Underlying: t.expr("")})
+		return nil
+	}
+	if b.kind(s) == "struct" {
+		return b.defineStruct(name, s)
+	}
+	if isStringEnum(s) {
+		return b.defineEnum(name, s)
+	}
+
+	t, err := b.typeOf(s, name)
+	if err != nil {
+		return err
+	}
+	b.api.Types = append(b.api.Types, &Type{
+		Name:       name,
+		Doc:        oneLine(s.Description),
+		Kind:       "alias",
+		Underlying: t.expr(""),
+		Checks:     b.checks("v", "", s, t, false, true),
+	})
+	return nil
+}
+
+func (b *builder) defineEnum(name string, s *Schema) error {
+	t := &Type{Name: name, Doc: oneLine(s.Description), Kind: "enum", Underlying: "string"}
+	used := map[string]bool{}
+	for _, raw := range s.Enum {
+		value := fmt.Sprint(raw)
+		constName := name + goName(value)
+		for i := 2; used[constName]; i++ {
+			constName = fmt.Sprintf("%s%s%d", name, goName(value), i)
+		}
+		used[constName] = true
+		t.Values = append(t.Values, &EnumValue{Name: constName, Value: value})
+	}
+	b.api.Types = append(b.api.Types, t)
+	return nil
+}
+
+func (b *builder) defineStruct(name string, s *Schema) error {
+	props, required, err := b.collect(s)
+	if err != nil {
+		return err
+	}
+
+	t := &Type{Name: name, Doc: oneLine(s.Description), Kind: "struct"}
+	fieldNames := map[string]string{}
+	for _, p := range props {
+		ft, err := b.typeOf(p.Schema, name+goName(p.Name))
+		if err != nil {
+			return fmt.Errorf("property %s: %w", p.Name, err)
+		}
+		isRequired := slices.Contains(required, p.Name)
+		nullable := p.Schema.Nullable || p.Schema.Type.Nullable
+		pointer := (!isRequired || nullable) && !ft.nilable()
+
+		field := &Field{Name: goName(p.Name), Type: ft.expr(""), Doc: oneLine(p.Schema.Description)}
+		if other, ok := fieldNames[field.Name]; ok {
+			return fmt.Errorf("properties %s and %s both map to field %s", other, p.Name, field.Name)
+		}
+		fieldNames[field.Name] = p.Name
+		if pointer {
+			field.Type = "*" + field.Type
+		}
+		tag := p.Name
+		if !isRequired {
+			tag += ",omitempty"
+		}
+		field.Tag = fmt.Sprintf("`json:%q`", tag)
+		field.Checks = b.checks("m."+field.Name, p.Name, p.Schema, ft, pointer, false)
+		t.Fields = append(t.Fields, field)
+		if isRequired {
+			t.Required = append(t.Required, p.Name)
+		}
+	}
+	if len(t.Required) > 0 {
+		b.api.NeedsRequireFields = true
+	}
+	b.api.Types = append(b.api.Types, t)
+	return nil
+}
+
+// collect merges the properties of s and its allOf parts.
+func (b *builder) collect(s *Schema) (Properties, []string, error) {
+	var (
+		props    Properties
+		required []string
+	)
+	for _, part := range s.AllOf {
+		_, resolved, err := b.doc.schema(part)
+		if err != nil {
+			return nil, nil, err
+		}
+		p, r, err := b.collect(resolved)
+		if err != nil {
+			return nil, nil, err
+		}
+		props = append(props, p...)
+		required = append(required, r...)
+	}
+	props = append(props, s.Properties...)
+	required = append(required, s.Required...)
+	return props, required, nil
+}
+
+// kind classifies a schema the way typeOf maps it, without declaring any
+// types.
+func (b *builder) kind(s *Schema) string {
+	if s == nil {
+		return "any"
+	}
+	_, s, err := b.doc.schema(s)
+	if err != nil {
+		return "any"
+	}
+	switch {
+	case len(s.AllOf) == 1 && len(s.Properties) == 0:
+		return b.kind(s.AllOf[0])
+	case len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+		return "any"
+	case isStringEnum(s):
+		return "string"
+	case len(s.Properties) > 0 || len(s.AllOf) > 0:
+		return "struct"
+	}
+	switch s.Type.Name {
+	case "object":
+		return "map"
+	case "array":
+		return "slice"
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "time"
+		case "byte":
+			return "bytes"
+		}
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "bool"
+	}
+	return "any"
+}
+
+func isStringEnum(s *Schema) bool {
+	return len(s.Enum) > 0 && (s.Type.Name == "string" || s.Type.Name == "")
+}
+
+// typeOf returns the Go type for s, declaring named types for inline
+// objects and enums under hint.
+func (b *builder) typeOf(s *Schema, hint string) (*goType, error) {
+	if s == nil {
+		return &goType{prim: "any", kind: "any"}, nil
+	}
+	if s.Ref != "" {
+		name, target, err := b.doc.schema(s)
+		if err != nil {
+			return nil, err
+		}
+		return &goType{name: b.components[name], kind: b.kind(target)}, nil
+	}
+
+	switch {
+	case len(s.AllOf) == 1 && len(s.Properties) == 0:
+		return b.typeOf(s.AllOf[0], hint)
+	case len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+		return &goType{prim: "any", kind: "any"}, nil
+	case isStringEnum(s):
+		if err := b.declare(hint); err != nil {
+			return nil, err
+		}
+		if err := b.defineEnum(hint, s); err != nil {
+			return nil, err
+		}
+		return &goType{name: hint, kind: "string"}, nil
+	case len(s.Properties) > 0 || len(s.AllOf) > 0:
+		if err := b.declare(hint); err != nil {
+			return nil, err
+		}
+		if err := b.defineStruct(hint, s); err != nil {
+			return nil, err
+		}
+		return &goType{name: hint, kind: "struct"}, nil
+	}
+
+	switch s.Type.Name {
+	case "object":
+		value := &goType{prim: "any", kind: "any"}
+		if ap := s.AdditionalProperties; ap != nil && ap.Schema != nil {
+			var err error
+			if value, err = b.typeOf(ap.Schema, hint+"Value"); err != nil {
+				return nil, err
+			}
+		}
+		return &goType{elem: value, isMap: true, kind: "map"}, nil
+	case "array":
+		item, err := b.typeOf(s.Items, hint+"Item")
+		if err != nil {
+			return nil, err
+		}
+		return &goType{elem: item, kind: "slice"}, nil
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return &goType{prim: "time.Time", kind: "time"}, nil
+		case "byte":
+			return &goType{prim: "[]byte", kind: "bytes"}, nil
+		}
+		return &goType{prim: "string", kind: "string"}, nil
+	case "integer":
+		return &goType{prim: integerType(s.Format), kind: "number"}, nil
+	case "number":
+		return &goType{prim: numberType(s.Format), kind: "number"}, nil
+	case "boolean":
+		return &goType{prim: "bool", kind: "bool"}, nil
+	}
+	return &goType{prim: "any", kind: "any"}, nil
+}
+
+func integerType(format string) string {
+	switch format {
+	case "int32":
+		return "int32"
+	case "int64":
+		return "int64"
+	}
+	return "int"
+}
+
+func numberType(format string) string {
+	if format == "float" {
+		return "float32"
+	}
+	return "float64"
+}
+
+// checks returns the statements Validate runs for one value. Inline
+// constraints are checked here; generated types validate themselves.
+// alias is set when expr is the receiver of a named non-struct type.
+func (b *builder) checks(expr, label string, s *Schema, t *goType, pointer, alias bool) []string {
+	val := expr
+	if pointer {
+		val = "*" + expr
+	}
+	prefix := ""
+	if label != "" {
+		prefix = strings.ReplaceAll(label, "%", "%%") + ": "
+	}
+	fail := func(message string) string {
+		return "errs = append(errs, errors.New(" + strconv.Quote(prefix+message) + "))"
+	}
+
+	var stmts []string
+	switch {
+	case t.name != "":
+		stmts = append(stmts, fmt.Sprintf("if err := %s.Validate(); err != nil {\nerrs = append(errs, fmt.Errorf(%s, err))\n}", expr, strconv.Quote(prefix+"%w")))
+	case t.elem != nil && t.elem.name != "":
+		index := "[%d]"
+		key := "i"
+		if t.isMap {
+			index, key = "[%q]", "key"
+		}
+		stmts = append(stmts, fmt.Sprintf("for %s, item := range %s {\nif err := item.Validate(); err != nil {\nerrs = append(errs, fmt.Errorf(%s, %s, err))\n}\n}",
+			key, val, strconv.Quote(strings.TrimSuffix(prefix, ": ")+index+": %w"), key))
+	}
+	if s.Ref != "" {
+		return b.wrap(expr, pointer, stmts)
+	}
+
+	switch t.kind {
+	case "string":
+		str := val
+		if alias {
+			str = "string(" + val + ")"
+		}
+		if s.MinLength != nil {
+			stmts = append(stmts, fmt.Sprintf("if utf8.RuneCountInString(%s) < %d {\n%s\n}", str, *s.MinLength, fail(fmt.Sprintf("must be at least %d characters long", *s.MinLength))))
+		}
+		if s.MaxLength != nil {
+			stmts = append(stmts, fmt.Sprintf("if utf8.RuneCountInString(%s) > %d {\n%s\n}", str, *s.MaxLength, fail(fmt.Sprintf("must be at most %d characters long", *s.MaxLength))))
+		}
+		if s.Pattern != "" {
+			stmts = append(stmts, fmt.Sprintf("if !%s.MatchString(%s) {\n%s\n}", b.pattern(s.Pattern), str, fail("must match "+s.Pattern)))
+		}
+	case "number":
+		if s.Minimum != nil {
+			n := strconv.FormatFloat(*s.Minimum, 'g', -1, 64)
+			stmts = append(stmts, fmt.Sprintf("if float64(%s) < %s {\n%s\n}", val, n, fail("must be at least "+n)))
+		}
+		if s.Maximum != nil {
+			n := strconv.FormatFloat(*s.Maximum, 'g', -1, 64)
+			stmts = append(stmts, fmt.Sprintf("if float64(%s) > %s {\n%s\n}", val, n, fail("must be at most "+n)))
+		}
+	case "slice":
+		if s.MinItems != nil {
+			stmts = append(stmts, fmt.Sprintf("if len(%s) < %d {\n%s\n}", val, *s.MinItems, fail(fmt.Sprintf("must have at least %d items", *s.MinItems))))
+		}
+		if s.MaxItems != nil {
+			stmts = append(stmts, fmt.Sprintf("if len(%s) > %d {\n%s\n}", val, *s.MaxItems, fail(fmt.Sprintf("must have at most %d items", *s.MaxItems))))
+		}
+	}
+	return b.wrap(expr, pointer, stmts)
+}
+
+func (b *builder) wrap(expr string, pointer bool, stmts []string) []string {
+	if !pointer || len(stmts) == 0 {
+		return stmts
+	}
+	return []string{"if " + expr + " != nil {\n" + strings.Join(stmts, "\n") + "\n}"}
+}
+
+func (b *builder) pattern(expr string) string {
+	if p, ok := b.patterns[expr]; ok {
+		return p.Var
+	}
+	p := &Pattern{Var: fmt.Sprintf("pattern%d", len(b.patterns)+1), Expr: expr}
+	b.patterns[expr] = p
+	b.api.Patterns = append(b.api.Patterns, p)
+	return p.Var
+}
+
+func (b *builder) endpoint(method, path string, item *PathItem, op *Operation) (*Endpoint, error) {
+	e := &Endpoint{
+		Name:    goName(op.OperationID),
+		Method:  method,
+		Path:    path,
+		Summary: oneLine(op.Summary),
+	}
+	if op.OperationID == "" {
+		e.Name = goName(strings.ToLower(method)) + pathName(path)
+	}
+	e.ColonPath, e.BracePath = routePaths(path)
+
+	params, err := b.params(item, op)
+	if err != nil {
+		return nil, err
+	}
+	body, bodyType, bodySchema, err := b.body(op, e.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(params) > 0 || body != nil {
+		name := e.Name + "Params"
+		if err := b.declare(name); err != nil {
+			return nil, err
+		}
+		t := &Type{Name: name, Kind: "params", Params: params, Body: body}
+		for _, p := range params {
+			t.Fields = append(t.Fields, p.field())
+		}
+		if body != nil {
+			field := &Field{Name: "Body", Type: bodyType.expr("")}
+			pointer := !body.Required && !bodyType.nilable()
+			if pointer {
+				field.Type = "*" + field.Type
+			}
+			if body.JSON {
+				field.Checks = b.checks("m.Body", "body", bodySchema, bodyType, pointer, false)
+			}
+			for _, f := range t.Fields {
+				if f.Name == "Body" {
+					return nil, fmt.Errorf("parameter %s collides with the request body field", f.Name)
+				}
+			}
+			t.Fields = append(t.Fields, field)
+		}
+		b.paramChecks(t)
+		b.api.Types = append(b.api.Types, t)
+		e.Params = t
+	}
+
+	return e, b.result(e, op)
+}
+
+func (p *Param) field() *Field {
+	typ := p.Kind
+	switch {
+	case p.List:
+		typ = "[]" + typ
+	case p.Pointer:
+		typ = "*" + typ
+	}
+	return &Field{Name: p.Field, Type: typ}
+}
+
+func (b *builder) params(item *PathItem, op *Operation) ([]*Param, error) {
+	var (
+		out    []*Param
+		byName = map[string]int{}
+		fields = map[string]string{}
+	)
+	for _, raw := range append(slices.Clone(item.Parameters), op.Parameters...) {
+		p, err := b.doc.parameter(raw)
+		if err != nil {
+			return nil, err
+		}
+		param, err := b.param(p)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+		// Operation parameters override path-level ones.
+		key := p.In + ":" + p.Name
+		if i, ok := byName[key]; ok {
+			out[i] = param
+			continue
+		}
+		if other, ok := fields[param.Field]; ok {
+			return nil, fmt.Errorf("parameters %s and %s both map to field %s", other, p.Name, param.Field)
+		}
+		fields[param.Field] = p.Name
+		byName[key] = len(out)
+		out = append(out, param)
+	}
+	return out, nil
+}
+
+func (b *builder) param(p *Parameter) (*Param, error) {
+	if p.Schema == nil {
+		return nil, fmt.Errorf("parameters without a schema are not supported")
+	}
+	_, s, err := b.doc.schema(p.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	param := &Param{
+		Name:     p.Name,
+		Key:      p.Name,
+		In:       p.In,
+		Field:    goName(p.Name),
+		Required: p.Required || p.In == "path",
+		Explode:  p.Explode == nil || *p.Explode,
+		schema:   s,
+	}
+	if p.In == "path" {
+		param.Key = wildcard(p.Name)
+	}
+	if s.Type.Name == "array" {
+		if p.In != "query" {
+			return nil, fmt.Errorf("array parameters are only supported in the query")
+		}
+		param.List = true
+		if s.Items == nil {
+			return nil, fmt.Errorf("array parameter without items")
+		}
+		if _, s, err = b.doc.schema(s.Items); err != nil {
+			return nil, err
+		}
+	}
+	switch s.Type.Name {
+	case "string", "":
+		param.Kind = "string"
+	case "integer":
+		param.Kind = integerType(s.Format)
+	case "number":
+		param.Kind = numberType(s.Format)
+	case "boolean":
+		param.Kind = "bool"
+	default:
+		return nil, fmt.Errorf("%s parameters are not supported", s.Type.Name)
+	}
+	param.Pointer = !param.Required && !param.List
+	return param, nil
+}
+
+// paramChecks adds the inline constraints of each parameter's schema to
+// its field.
+func (b *builder) paramChecks(t *Type) {
+	for i, p := range t.Params {
+		kind := "number"
+		switch {
+		case p.List:
+			kind = "slice"
+		case p.Kind == "string":
+			kind = "string"
+		case p.Kind == "bool":
+			kind = "bool"
+		}
+		checks := b.checks("m."+p.Field, p.Name, p.schema, &goType{prim: p.Kind, kind: kind}, p.Pointer, false)
+		if len(p.schema.Enum) > 0 && p.Kind == "string" && !p.List {
+			checks = append(checks, b.enumCheck("m."+p.Field, p.Name, p.schema.Enum, p.Pointer)...)
+		}
+		t.Fields[i].Checks = checks
+	}
+}
+
+func (b *builder) enumCheck(expr, label string, enum []any, pointer bool) []string {
+	values := make([]string, len(enum))
+	quoted := make([]string, len(enum))
+	for i, v := range enum {
+		values[i] = fmt.Sprint(v)
+		quoted[i] = strconv.Quote(values[i])
+	}
+	val := expr
+	if pointer {
+		val = "*" + expr
+	}
+	stmt := fmt.Sprintf("switch %s {\ncase %s:\ndefault:\nerrs = append(errs, errors.New(%s))\n}",
+		val, strings.Join(quoted, ", "), strconv.Quote(label+": must be one of "+strings.Join(values, ", ")))
+	return b.wrap(expr, pointer, []string{stmt})
+}
+
+func (b *builder) body(op *Operation, opName string) (*Body, *goType, *Schema, error) {
+	if op.RequestBody == nil {
+		return nil, nil, nil, nil
+	}
+	rb, err := b.doc.requestBody(op.RequestBody)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	body := &Body{Required: rb.Required}
+	media, ok := jsonMedia(rb.Content)
+	if !ok {
+		return body, &goType{prim: "[]byte", kind: "bytes"}, &Schema{}, nil
+	}
+	body.JSON = true
+	t, err := b.typeOf(media.Schema, opName+"Body")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("request body: %w", err)
+	}
+	schema := media.Schema
+	if schema == nil {
+		schema = &Schema{}
+	}
+	return body, t, schema, nil
+}
+
+// successResponse picks the lowest 2xx response, falling back to the
+// default one, and returns it with its status.
+func successResponse(op *Operation) (*Response, int) {
+	var (
+		chosen *Response
+		best   = 1000
+	)
+	for code, resp := range op.Responses {
+		n, err := strconv.Atoi(code)
+		switch {
+		case err == nil && n >= 200 && n < 300 && n < best:
+			best, chosen = n, resp
+		case strings.EqualFold(code, "2XX") && best == 1000:
+			best, chosen = 200, resp
+		}
+	}
+	if chosen == nil {
+		return op.Responses["default"], 200
+	}
+	return chosen, best
+}
+
+func (b *builder) result(e *Endpoint, op *Operation) error {
+	chosen, status := successResponse(op)
+	e.Status = status
+	if chosen == nil {
+		return nil
+	}
+
+	resp, err := b.doc.response(chosen)
+	if err != nil {
+		return err
+	}
+	media, ok := jsonMedia(resp.Content)
+	if !ok || media.Schema == nil {
+		return nil
+	}
+	t, err := b.typeOf(media.Schema, e.Name+"Response")
+	if err != nil {
+		return fmt.Errorf("response: %w", err)
+	}
+	e.Result = t.expr("model.")
+	switch t.kind {
+	case "struct", "time":
+		e.Result = "*" + e.Result
+		e.Zero = "nil"
+	case "string":
+		e.Zero = `""`
+	case "number":
+		e.Zero = "0"
+	case "bool":
+		e.Zero = "false"
+	default:
+		e.Zero = "nil"
+	}
+	return nil
+}
+
+func jsonMedia(content map[string]*MediaType) (*MediaType, bool) {
+	if media, ok := content["application/json"]; ok {
+		return media, true
+	}
+	keys := make([]string, 0, len(content))
+	for key := range content {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if strings.HasSuffix(key, "+json") {
+			return content[key], true
+		}
+	}
+	return nil, false
+}
+
+func mediaTypes(content map[string]*MediaType) map[string]bool {
+	types := make(map[string]bool, len(content))
+	for key := range content {
+		types[key] = true
+	}
+	return types
+}
+
+// pathName names an operation without an operationId after its path:
+// /pets/{petId}/photos becomes PetsByPetIDPhotos.
+func pathName(path string) string {
+	var b strings.Builder
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			b.WriteString("By" + goName(strings.Trim(segment, "{}")))
+			continue
+		}
+		if segment != "" {
+			b.WriteString(goName(segment))
+		}
+	}
+	return b.String()
+}
+
+// routePaths rewrites a spec path for :param routers (gin, echo, fiber)
+// and {param} routers (chi, gorilla, net/http).
+func routePaths(path string) (colon, brace string) {
+	segments := strings.Split(path, "/")
+	colonSegments := make([]string, len(segments))
+	braceSegments := make([]string, len(segments))
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name := wildcard(strings.Trim(segment, "{}"))
+			colonSegments[i] = ":" + name
+			braceSegments[i] = "{" + name + "}"
+			continue
+		}
+		colonSegments[i] = segment
+		braceSegments[i] = segment
+	}
+	return strings.Join(colonSegments, "/"), strings.Join(braceSegments, "/")
+}
+
+func (b *builder) resolveImports() {
+	model := map[string]bool{}
+	handler := map[string]bool{"errors": true, "fmt": true, "net/http": true, "strings": true}
+	if len(b.api.Endpoints) > 0 {
+		handler["context"] = true
+	}
+
+	if b.api.NeedsRequireFields {
+		model["encoding/json"] = true
+		model["fmt"] = true
+	}
+	if len(b.api.Patterns) > 0 {
+		model["regexp"] = true
+	}
+	for _, t := range b.api.Types {
+		if t.Kind == "enum" {
+			model["fmt"] = true
+		}
+		if t.HasChecks() {
+			model["errors"] = true
+		}
+		code := strings.Join(t.Checks, "\n")
+		for _, f := range t.Fields {
+			code += f.Type + "\n" + strings.Join(f.Checks, "\n")
+		}
+		code += t.Underlying
+		for pkg, marker := range map[string]string{"fmt": "fmt.", "unicode/utf8": "utf8.", "time": "time."} {
+			if strings.Contains(code, marker) {
+				model[pkg] = true
+			}
+		}
+		for _, p := range t.Params {
+			if p.Kind != "string" {
+				handler["strconv"] = true
+			}
+		}
+		if t.Body != nil {
+			handler["bytes"] = true
+			if t.Body.JSON {
+				handler["encoding/json"] = true
+			}
+		}
+	}
+	for _, e := range b.api.Endpoints {
+		if e.Params != nil || strings.Contains(e.Result, "model.") {
+			b.api.HandlerUsesModel = true
+		}
+		if strings.Contains(e.Result, "time.") {
+			handler["time"] = true
+		}
+	}
+
+	b.api.ModelImports = sortedKeys(model)
+	b.api.HandlerImports = sortedKeys(handler)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,629 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const petstore = `
+openapi: 3.1.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /:
+    get:
+      responses: {"200": {description: ok}}
+  /health:
+    get:
+      responses: {"200": {description: ok}}
+  /docs/openapi.yaml:
+    get:
+      responses: {"200": {description: ok}}
+  /todos:
+    get:
+      x-gpm-manual: true
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Todo"}
+  /pets:
+    get:
+      operationId: list-pets
+      summary: |
+        List pets
+        in the store.
+      parameters:
+        - {$ref: "#/components/parameters/Limit"}
+        - name: tags
+          in: query
+          explode: false
+          schema: {type: array, items: {type: string}, maxItems: 5}
+        - name: status
+          in: query
+          schema: {type: string, enum: [available, sold]}
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+        default: {$ref: "#/components/responses/Error"}
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/NewPet"}
+      responses:
+        "201":
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+        "202": {description: queued}
+  /pets/{pet-id}:
+    parameters:
+      - name: pet-id
+        in: path
+        schema: {type: integer, format: int64, minimum: 1}
+    get:
+      parameters:
+        - name: X-Request-ID
+          in: header
+          schema: {type: string, format: uuid}
+      responses:
+        "200":
+          content:
+            application/problem+json:
+              schema: {$ref: "#/components/schemas/Pet"}
+    delete:
+      operationId: deletePet
+      parameters:
+        - {name: pet-id, in: path, schema: {type: integer, format: int32}}
+      responses: {"204": {description: deleted}}
+  /pets/{pet-id}/photo:
+    put:
+      operationId: uploadPhoto
+      parameters:
+        - name: pet-id
+          in: path
+          required: true
+          schema: {type: integer, format: int64}
+      requestBody:
+        content:
+          image/png: {}
+      responses:
+        "2XX":
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  url: {type: string, maxLength: 2048}
+                  size: {type: integer}
+                required: [url]
+    get:
+      operationId: downloadPhoto
+      responses:
+        "200":
+          content:
+            image/png: {}
+            text/plain: {}
+  /pets/{pet-id}/name:
+    get:
+      operationId: petName
+      parameters:
+        - {name: pet-id, in: path, schema: {type: integer}}
+      responses:
+        "200":
+          content:
+            text/plain:
+              schema: {type: string}
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: query
+      schema: {type: integer, format: int32, minimum: 1, maximum: 100}
+  responses:
+    Error:
+      content:
+        application/json:
+          schema: {$ref: "#/components/schemas/Error"}
+  schemas:
+    Todo:
+      type: object
+      properties: {title: {type: string}}
+    Error:
+      type: object
+      properties: {message: {type: string}}
+      required: [message]
+    Name:
+      type: string
+      minLength: 1
+      maxLength: 64
+      pattern: "^[A-Za-z ]+$"
+    PetName: {$ref: "#/components/schemas/Name"}
+    Species:
+      type: string
+      enum: [dog, cat, hot-dog, hot_dog]
+    NewPet:
+      description: A pet to add.
+      type: object
+      required: [name, species]
+      properties:
+        name: {$ref: "#/components/schemas/PetName"}
+        species: {$ref: "#/components/schemas/Species"}
+        tag:
+          type: [string, "null"]
+          pattern: "^[a-z]+$"
+        nickname:
+          type: string
+          nullable: true
+        born: {type: string, format: date-time}
+        photo: {type: string, format: byte}
+        weight: {type: number, format: float, minimum: 0.5}
+        owner:
+          type: object
+          properties:
+            email: {type: string, pattern: "^[a-z]+$"}
+        toys:
+          type: array
+          minItems: 1
+          items: {$ref: "#/components/schemas/Toy"}
+        labels:
+          type: object
+          additionalProperties: {type: string}
+        extra: {}
+    Pet:
+      allOf:
+        - {$ref: "#/components/schemas/NewPet"}
+        - type: object
+          required: [id]
+          properties:
+            id: {type: integer, format: int64}
+            vaccinated: {type: [boolean, "null"]}
+    Toy:
+      oneOf:
+        - {type: string}
+        - {type: object, properties: {kind: {type: string}}}
+`
+
+func buildSpec(t *testing.T, spec string) *API {
+	t.Helper()
+	doc, err := Parse([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api, err := Build(doc, "api/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api
+}
+
+func findType(t *testing.T, api *API, name string) *Type {
+	t.Helper()
+	for _, typ := range api.Types {
+		if typ.Name == name {
+			return typ
+		}
+	}
+	t.Fatalf("type %s was not generated", name)
+	return nil
+}
+
+func findField(t *testing.T, typ *Type, name string) *Field {
+	t.Helper()
+	for _, f := range typ.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	t.Fatalf("%s has no field %s", typ.Name, name)
+	return nil
+}
+
+func TestBuildEndpoints(t *testing.T) {
+	api := buildSpec(t, petstore)
+
+	type endpoint struct {
+		Name, Method, Path, ColonPath, BracePath, Summary string
+		Params                                            string
+		Result, Zero                                      string
+		Status                                            int
+	}
+	want := []endpoint{
+		{Name: "ListPets", Method: "GET", Path: "/pets", ColonPath: "/pets", BracePath: "/pets", Summary: "List pets in the store.",
+			Params: "ListPetsParams", Result: "[]model.Pet", Zero: "nil", Status: 200},
+		{Name: "CreatePet", Method: "POST", Path: "/pets", ColonPath: "/pets", BracePath: "/pets",
+			Params: "CreatePetParams", Result: "*model.Pet", Zero: "nil", Status: 201},
+		{Name: "GetPetsByPetID", Method: "GET", Path: "/pets/{pet-id}", ColonPath: "/pets/:pet_id", BracePath: "/pets/{pet_id}",
+			Params: "GetPetsByPetIDParams", Result: "*model.Pet", Zero: "nil", Status: 200},
+		{Name: "DeletePet", Method: "DELETE", Path: "/pets/{pet-id}", ColonPath: "/pets/:pet_id", BracePath: "/pets/{pet_id}",
+			Params: "DeletePetParams", Status: 204},
+		{Name: "UploadPhoto", Method: "PUT", Path: "/pets/{pet-id}/photo", ColonPath: "/pets/:pet_id/photo", BracePath: "/pets/{pet_id}/photo",
+			Params: "UploadPhotoParams", Result: "*model.UploadPhotoResponse", Zero: "nil", Status: 200},
+	}
+
+	var got []endpoint
+	for _, e := range api.Endpoints {
+		params := ""
+		if e.Params != nil {
+			params = e.Params.Name
+		}
+		got = append(got, endpoint{e.Name, e.Method, e.Path, e.ColonPath, e.BracePath, e.Summary, params, e.Result, e.Zero, e.Status})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("endpoints:\n%+v\nwant:\n%+v", got, want)
+	}
+}
+
+func TestBuildSkipped(t *testing.T) {
+	api := buildSpec(t, petstore)
+
+	want := []string{
+		"GET / (served by NewRouter)",
+		"GET /docs/openapi.yaml (served by NewRouter)",
+		"GET /health (served by NewRouter)",
+		"GET /pets/{pet-id}/name (responds with text/plain; only JSON responses are generated)",
+		"GET /pets/{pet-id}/photo (responds with image/png, text/plain; only JSON responses are generated)",
+		"GET /todos (x-gpm-manual)",
+	}
+	if !reflect.DeepEqual(api.Skipped, want) {
+		t.Errorf("Skipped:\n%s\nwant:\n%s", strings.Join(api.Skipped, "\n"), strings.Join(want, "\n"))
+	}
+	for _, typ := range api.Types {
+		if typ.Name == "Todo" || typ.Name == "PetNameParams" || typ.Name == "DownloadPhotoParams" {
+			t.Errorf("%s was generated for a skipped operation", typ.Name)
+		}
+	}
+}
+
+func TestBuildTypes(t *testing.T) {
+	api := buildSpec(t, petstore)
+
+	var names []string
+	for _, typ := range api.Types {
+		names = append(names, typ.Name+":"+typ.Kind)
+	}
+	// Components come first in name order, then the types each endpoint
+	// declares. Inline objects are declared under their parent's name, so
+	// allOf gives Pet its own copy of NewPet's owner.
+	wantNames := []string{
+		"Error:struct", "Name:alias", "NewPetOwner:struct", "NewPet:struct", "PetOwner:struct", "Pet:struct",
+		"PetName:typealias", "Species:enum", "Toy:alias",
+		"ListPetsParams:params", "CreatePetParams:params", "GetPetsByPetIDParams:params",
+		"DeletePetParams:params", "UploadPhotoParams:params", "UploadPhotoResponse:struct",
+	}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("types:\n%v\nwant:\n%v", names, wantNames)
+	}
+
+	tests := []struct {
+		typ, field string
+		wantType   string
+		wantTag    string
+	}{
+		// A chain of $refs resolves to the component at its end.
+		{"NewPet", "Name", "Name", "`json:\"name\"`"},
+		{"NewPet", "Species", "Species", "`json:\"species\"`"},
+		{"NewPet", "Tag", "*string", "`json:\"tag,omitempty\"`"},
+		{"NewPet", "Nickname", "*string", "`json:\"nickname,omitempty\"`"},
+		{"NewPet", "Born", "*time.Time", "`json:\"born,omitempty\"`"},
+		{"NewPet", "Photo", "[]byte", "`json:\"photo,omitempty\"`"},
+		{"NewPet", "Weight", "*float32", "`json:\"weight,omitempty\"`"},
+		{"NewPet", "Owner", "*NewPetOwner", "`json:\"owner,omitempty\"`"},
+		{"NewPet", "Toys", "[]Toy", "`json:\"toys,omitempty\"`"},
+		{"NewPet", "Labels", "map[string]string", "`json:\"labels,omitempty\"`"},
+		{"NewPet", "Extra", "any", "`json:\"extra,omitempty\"`"},
+		// allOf merges the parts' properties and required lists.
+		{"Pet", "Name", "Name", "`json:\"name\"`"},
+		{"Pet", "Owner", "*PetOwner", "`json:\"owner,omitempty\"`"},
+		{"Pet", "ID", "int64", "`json:\"id\"`"},
+		{"Pet", "Vaccinated", "*bool", "`json:\"vaccinated,omitempty\"`"},
+		{"UploadPhotoResponse", "URL", "string", "`json:\"url\"`"},
+		{"UploadPhotoResponse", "Size", "*int", "`json:\"size,omitempty\"`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ+"."+tt.field, func(t *testing.T) {
+			f := findField(t, findType(t, api, tt.typ), tt.field)
+			if f.Type != tt.wantType || f.Tag != tt.wantTag {
+				t.Errorf("field = %s %s, want %s %s", f.Type, f.Tag, tt.wantType, tt.wantTag)
+			}
+		})
+	}
+
+	if got := findType(t, api, "Pet").Required; !reflect.DeepEqual(got, []string{"name", "species", "id"}) {
+		t.Errorf("Pet.Required = %v, want the merged allOf list", got)
+	}
+	if got := findType(t, api, "NewPet").Doc; got != "A pet to add." {
+		t.Errorf("NewPet.Doc = %q", got)
+	}
+	if got := findType(t, api, "PetName").Underlying; got != "Name" {
+		t.Errorf("PetName aliases %q, want Name", got)
+	}
+	if got := findType(t, api, "Toy").Underlying; got != "any" {
+		t.Errorf("oneOf maps to %q, want any", got)
+	}
+
+	var values []string
+	for _, v := range findType(t, api, "Species").Values {
+		values = append(values, v.Name+"="+v.Value)
+	}
+	wantValues := []string{"SpeciesDog=dog", "SpeciesCat=cat", "SpeciesHotDog=hot-dog", "SpeciesHotDog2=hot_dog"}
+	if !reflect.DeepEqual(values, wantValues) {
+		t.Errorf("Species values = %v, want %v", values, wantValues)
+	}
+}
+
+func TestBuildParams(t *testing.T) {
+	api := buildSpec(t, petstore)
+
+	type param struct {
+		Name, Key, In, Field, Kind       string
+		Required, List, Pointer, Explode bool
+	}
+	tests := []struct {
+		typ  string
+		want []param
+		body *Body
+	}{
+		{
+			typ: "ListPetsParams",
+			want: []param{
+				{"limit", "limit", "query", "Limit", "int32", false, false, true, true},
+				{"tags", "tags", "query", "Tags", "string", false, true, false, false},
+				{"status", "status", "query", "Status", "string", false, false, true, true},
+			},
+		},
+		{
+			typ:  "CreatePetParams",
+			body: &Body{Required: true, JSON: true},
+		},
+		{
+			// Path parameters are required even without required: true.
+			typ: "GetPetsByPetIDParams",
+			want: []param{
+				{"pet-id", "pet_id", "path", "PetID", "int64", true, false, false, true},
+				{"X-Request-ID", "X-Request-ID", "header", "XRequestID", "string", false, false, true, true},
+			},
+		},
+		{
+			// The operation's pet-id replaces the path-level one.
+			typ:  "DeletePetParams",
+			want: []param{{"pet-id", "pet_id", "path", "PetID", "int32", true, false, false, true}},
+		},
+		{
+			typ:  "UploadPhotoParams",
+			want: []param{{"pet-id", "pet_id", "path", "PetID", "int64", true, false, false, true}},
+			body: &Body{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			typ := findType(t, api, tt.typ)
+			var got []param
+			for _, p := range typ.Params {
+				got = append(got, param{p.Name, p.Key, p.In, p.Field, p.Kind, p.Required, p.List, p.Pointer, p.Explode})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("params:\n%+v\nwant:\n%+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(typ.Body, tt.body) {
+				t.Errorf("Body = %+v, want %+v", typ.Body, tt.body)
+			}
+		})
+	}
+
+	if got := findField(t, findType(t, api, "UploadPhotoParams"), "Body").Type; got != "[]byte" {
+		t.Errorf("non-JSON body field has type %s, want []byte", got)
+	}
+	if got := findField(t, findType(t, api, "CreatePetParams"), "Body").Type; got != "NewPet" {
+		t.Errorf("required JSON body field has type %s, want NewPet", got)
+	}
+}
+
+func TestBuildValidation(t *testing.T) {
+	api := buildSpec(t, petstore)
+
+	tests := []struct {
+		typ, field string
+		want       []string
+	}{
+		{"Name", "", []string{
+			"if utf8.RuneCountInString(string(v)) < 1 {\nerrs = append(errs, errors.New(\"must be at least 1 characters long\"))\n}",
+			"if utf8.RuneCountInString(string(v)) > 64 {\nerrs = append(errs, errors.New(\"must be at most 64 characters long\"))\n}",
+			"if !pattern1.MatchString(string(v)) {\nerrs = append(errs, errors.New(\"must match ^[A-Za-z ]+$\"))\n}",
+		}},
+		{"NewPet", "Name", []string{
+			"if err := m.Name.Validate(); err != nil {\nerrs = append(errs, fmt.Errorf(\"name: %w\", err))\n}",
+		}},
+		{"NewPet", "Tag", []string{
+			"if m.Tag != nil {\nif !pattern2.MatchString(*m.Tag) {\nerrs = append(errs, errors.New(\"tag: must match ^[a-z]+$\"))\n}\n}",
+		}},
+		{"NewPet", "Weight", []string{
+			"if m.Weight != nil {\nif float64(*m.Weight) < 0.5 {\nerrs = append(errs, errors.New(\"weight: must be at least 0.5\"))\n}\n}",
+		}},
+		{"NewPet", "Owner", []string{
+			"if m.Owner != nil {\nif err := m.Owner.Validate(); err != nil {\nerrs = append(errs, fmt.Errorf(\"owner: %w\", err))\n}\n}",
+		}},
+		{"NewPet", "Toys", []string{
+			"for i, item := range m.Toys {\nif err := item.Validate(); err != nil {\nerrs = append(errs, fmt.Errorf(\"toys[%d]: %w\", i, err))\n}\n}",
+			"if len(m.Toys) < 1 {\nerrs = append(errs, errors.New(\"toys: must have at least 1 items\"))\n}",
+		}},
+		{"NewPet", "Labels", nil},
+		// Patterns are declared once and shared.
+		{"NewPetOwner", "Email", []string{
+			"if m.Email != nil {\nif !pattern2.MatchString(*m.Email) {\nerrs = append(errs, errors.New(\"email: must match ^[a-z]+$\"))\n}\n}",
+		}},
+		{"ListPetsParams", "Limit", []string{
+			"if m.Limit != nil {\nif float64(*m.Limit) < 1 {\nerrs = append(errs, errors.New(\"limit: must be at least 1\"))\n}\nif float64(*m.Limit) > 100 {\nerrs = append(errs, errors.New(\"limit: must be at most 100\"))\n}\n}",
+		}},
+		{"ListPetsParams", "Tags", []string{
+			"if len(m.Tags) > 5 {\nerrs = append(errs, errors.New(\"tags: must have at most 5 items\"))\n}",
+		}},
+		{"ListPetsParams", "Status", []string{
+			"if m.Status != nil {\nswitch *m.Status {\ncase \"available\", \"sold\":\ndefault:\nerrs = append(errs, errors.New(\"status: must be one of available, sold\"))\n}\n}",
+		}},
+		{"CreatePetParams", "Body", []string{
+			"if err := m.Body.Validate(); err != nil {\nerrs = append(errs, fmt.Errorf(\"body: %w\", err))\n}",
+		}},
+		{"UploadPhotoParams", "Body", nil},
+		{"UploadPhotoResponse", "URL", []string{
+			"if utf8.RuneCountInString(m.URL) > 2048 {\nerrs = append(errs, errors.New(\"url: must be at most 2048 characters long\"))\n}",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ+"."+tt.field, func(t *testing.T) {
+			typ := findType(t, api, tt.typ)
+			got := typ.Checks
+			if tt.field != "" {
+				got = findField(t, typ, tt.field).Checks
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checks:\n%s\nwant:\n%s", strings.Join(got, "\n---\n"), strings.Join(tt.want, "\n---\n"))
+			}
+		})
+	}
+
+	var patterns []string
+	for _, p := range api.Patterns {
+		patterns = append(patterns, p.Var+"="+p.Expr)
+	}
+	if want := []string{"pattern1=^[A-Za-z ]+$", "pattern2=^[a-z]+$"}; !reflect.DeepEqual(patterns, want) {
+		t.Errorf("Patterns = %v, want %v", patterns, want)
+	}
+}
+
+func TestBuildImports(t *testing.T) {
+	api := buildSpec(t, petstore)
+
+	wantModel := []string{"encoding/json", "errors", "fmt", "regexp", "time", "unicode/utf8"}
+	if !reflect.DeepEqual(api.ModelImports, wantModel) {
+		t.Errorf("ModelImports = %v, want %v", api.ModelImports, wantModel)
+	}
+	wantHandler := []string{"bytes", "context", "encoding/json", "errors", "fmt", "net/http", "strconv", "strings"}
+	if !reflect.DeepEqual(api.HandlerImports, wantHandler) {
+		t.Errorf("HandlerImports = %v, want %v", api.HandlerImports, wantHandler)
+	}
+	if !api.HandlerUsesModel || !api.NeedsRequireFields {
+		t.Errorf("HandlerUsesModel = %v, NeedsRequireFields = %v; want both set", api.HandlerUsesModel, api.NeedsRequireFields)
+	}
+}
+
+func TestBuildIsDeterministic(t *testing.T) {
+	first := buildSpec(t, petstore)
+	for range 20 {
+		if next := buildSpec(t, petstore); !reflect.DeepEqual(next, first) {
+			t.Fatal("Build returned a different API for the same document")
+		}
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		paths   string
+		schemas string
+		wantErr string
+	}{
+		{
+			name: "unresolved schema",
+			paths: `
+  /pets:
+    get:
+      responses: {"200": {content: {application/json: {schema: {$ref: "#/components/schemas/Missing"}}}}}`,
+			wantErr: "unresolved reference #/components/schemas/Missing",
+		},
+		{
+			name: "unresolved response",
+			paths: `
+  /pets:
+    get:
+      responses: {"200": {$ref: "#/components/responses/Missing"}}`,
+			wantErr: "unresolved reference #/components/responses/Missing",
+		},
+		{
+			name: "remote reference",
+			paths: `
+  /pets:
+    post:
+      requestBody: {content: {application/json: {schema: {$ref: "pet.yaml#/Pet"}}}}
+      responses: {"204": {description: ok}}`,
+			wantErr: "only local #/components/schemas/",
+		},
+		{
+			name: "duplicate operation names",
+			paths: `
+  /pets:
+    get: {operationId: listPets, responses: {"200": {description: ok}}}
+  /animals:
+    get: {operationId: list_pets, responses: {"200": {description: ok}}}`,
+			wantErr: "both map to ListPets",
+		},
+		{
+			name: "schema clashes with a generated type",
+			paths: `
+  /pets:
+    get:
+      operationId: listPets
+      parameters: [{name: limit, in: query, schema: {type: integer}}]
+      responses: {"200": {content: {application/json: {schema: {$ref: "#/components/schemas/ListPetsParams"}}}}}`,
+			schemas: `
+    ListPetsParams: {type: string}`,
+			wantErr: "ListPetsParams is used more than once",
+		},
+		{
+			name: "colliding fields",
+			paths: `
+  /pets:
+    get:
+      parameters:
+        - {name: pet_id, in: query, schema: {type: string}}
+        - {name: petId, in: query, schema: {type: string}}
+      responses: {"200": {description: ok}}`,
+			wantErr: "both map to field PetID",
+		},
+		{
+			name: "array path parameter",
+			paths: `
+  /pets/{ids}:
+    get:
+      parameters: [{name: ids, in: path, schema: {type: array, items: {type: string}}}]
+      responses: {"200": {description: ok}}`,
+			wantErr: "only supported in the query",
+		},
+		{
+			name: "object parameter",
+			paths: `
+  /pets:
+    get:
+      parameters: [{name: filter, in: query, schema: {type: object}}]
+      responses: {"200": {description: ok}}`,
+			wantErr: "object parameters are not supported",
+		},
+		{
+			name: "parameter without schema",
+			paths: `
+  /pets:
+    get:
+      parameters: [{name: q, in: query}]
+      responses: {"200": {description: ok}}`,
+			wantErr: "without a schema",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := "openapi: 3.0.3\npaths:" + tt.paths + "\ncomponents:\n  schemas:" + tt.schemas + "\n    Unused: {type: string}\n"
+			doc, err := Parse([]byte(spec))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Build(doc, "api/openapi.yaml"); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
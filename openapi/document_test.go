@@ -0,0 +1,162 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{name: "3.0", spec: "openapi: 3.0.3\npaths: {}"},
+		{name: "3.1", spec: "openapi: 3.1.0\npaths: {}"},
+		{name: "json", spec: `{"openapi": "3.1.0", "paths": {}}`},
+		{name: "swagger", spec: "swagger: '2.0'", wantErr: "convert to OpenAPI 3"},
+		{name: "missing version", spec: "paths: {}", wantErr: "expected 3.x"},
+		{name: "invalid yaml", spec: "openapi: [", wantErr: "failed to parse"},
+		{name: "properties as a list", spec: "openapi: 3.1.0\ncomponents:\n  schemas:\n    Pet:\n      properties: [name]", wantErr: "properties must be a mapping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.spec))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(path, []byte("openapi: 3.0.3\ninfo: {title: Pets, version: 1.0.0}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Info.Title != "Pets" {
+		t.Errorf("Info.Title = %q, want Pets", doc.Info.Title)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil || !strings.Contains(err.Error(), "failed to read") {
+		t.Errorf("Load of a missing file: error = %v", err)
+	}
+}
+
+func TestSchemaDecoding(t *testing.T) {
+	doc, err := Parse([]byte(`
+openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      properties:
+        name: {type: string}
+        tag: {type: [string, "null"]}
+        age: {type: integer}
+        id: {type: ["null", integer]}
+        labels:
+          type: object
+          additionalProperties: {type: string}
+        extra:
+          type: object
+          additionalProperties: false
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	props := doc.Components.Schemas["Pet"].Properties
+
+	var order []string
+	for _, p := range props {
+		order = append(order, p.Name)
+	}
+	if got := strings.Join(order, ","); got != "name,tag,age,id,labels,extra" {
+		t.Errorf("properties decoded as %s, want the declared order", got)
+	}
+
+	tests := []struct {
+		index        int
+		wantType     string
+		wantNullable bool
+	}{
+		{0, "string", false},
+		{1, "string", true},
+		{2, "integer", false},
+		{3, "integer", true},
+	}
+	for _, tt := range tests {
+		got := props[tt.index].Schema.Type
+		if got.Name != tt.wantType || got.Nullable != tt.wantNullable {
+			t.Errorf("%s: type = %+v, want %s (nullable %v)", props[tt.index].Name, got, tt.wantType, tt.wantNullable)
+		}
+	}
+
+	if ap := props[4].Schema.AdditionalProperties; !ap.Allowed || ap.Schema == nil || ap.Schema.Type.Name != "string" {
+		t.Errorf("labels: additionalProperties = %+v, want a string schema", ap)
+	}
+	if ap := props[5].Schema.AdditionalProperties; ap.Allowed || ap.Schema != nil {
+		t.Errorf("extra: additionalProperties = %+v, want disallowed", ap)
+	}
+}
+
+func TestResolveReferences(t *testing.T) {
+	doc, err := Parse([]byte(`
+openapi: 3.0.3
+components:
+  schemas:
+    Pet: {type: object, properties: {name: {type: string}}}
+    Animal: {$ref: "#/components/schemas/Pet"}
+    Creature: {$ref: "#/components/schemas/Animal"}
+  parameters:
+    Limit: {name: limit, in: query, schema: {type: integer}}
+    PageSize: {$ref: "#/components/parameters/Limit"}
+  requestBodies:
+    NewPet: {required: true, content: {application/json: {schema: {$ref: "#/components/schemas/Pet"}}}}
+  responses:
+    PetResponse: {content: {application/json: {schema: {$ref: "#/components/schemas/Pet"}}}}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, s, err := doc.schema(&Schema{Ref: "#/components/schemas/Creature"})
+	if err != nil || name != "Pet" || s != doc.Components.Schemas["Pet"] {
+		t.Errorf("schema(Creature) = %q, %v, %v; want the Pet component", name, s, err)
+	}
+	if p, err := doc.parameter(&Parameter{Ref: "#/components/parameters/PageSize"}); err != nil || p.Name != "limit" {
+		t.Errorf("parameter(PageSize) = %+v, %v; want limit", p, err)
+	}
+	if b, err := doc.requestBody(&RequestBody{Ref: "#/components/requestBodies/NewPet"}); err != nil || !b.Required {
+		t.Errorf("requestBody(NewPet) = %+v, %v", b, err)
+	}
+	if r, err := doc.response(&Response{Ref: "#/components/responses/PetResponse"}); err != nil || r.Content["application/json"] == nil {
+		t.Errorf("response(PetResponse) = %+v, %v", r, err)
+	}
+
+	bad := []struct {
+		ref     string
+		wantErr string
+	}{
+		{"#/components/schemas/Missing", "unresolved reference"},
+		{"#/components/parameters/Limit", "only local #/components/schemas/"},
+		{"other.yaml#/components/schemas/Pet", "unsupported reference"},
+	}
+	for _, tt := range bad {
+		if _, _, err := doc.schema(&Schema{Ref: tt.ref}); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("schema(%s): error = %v, want it to contain %q", tt.ref, err, tt.wantErr)
+		}
+	}
+}
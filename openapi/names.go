@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"strings"
+	"unicode"
+)
+
+// initialisms are upper-cased whole, following Go naming conventions.
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "IP": true,
+	"JSON": true, "SQL": true, "URI": true, "URL": true, "UUID": true,
+}
+
+// goName turns an identifier from the spec (pet_id, list-pets, petId)
+// into an exported Go name (PetID, ListPets, PetID).
+func goName(s string) string {
+	var b strings.Builder
+	for _, word := range words(s) {
+		upper := strings.ToUpper(word)
+		if initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// words splits on anything that is not a letter or digit and on
+// lower-to-upper case changes.
+func words(s string) []string {
+	var (
+		out  []string
+		word []rune
+	)
+	flush := func() {
+		if len(word) > 0 {
+			out = append(out, string(word))
+			word = word[:0]
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+	return out
+}
+
+// wildcard makes a path parameter name usable as a route wildcard in every
+// supported router, including net/http's ServeMux.
+func wildcard(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// oneLine collapses a description so it fits in a // comment.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,70 @@
+package openapi
+
+import "testing"
+
+func TestGoName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"pet_id", "PetID"},
+		{"list-pets", "ListPets"},
+		{"petId", "PetID"},
+		{"listPets", "ListPets"},
+		{"getAPIKey", "GetAPIKey"},
+		{"user.url", "UserURL"},
+		{"HTTPStatus", "HTTPStatus"},
+		{"http_status", "HTTPStatus"},
+		{"x-request-id", "XRequestID"},
+		{"2fa", "X2fa"},
+		{"", "X"},
+		{"café_owner", "CaféOwner"},
+	}
+	for _, tt := range tests {
+		if got := goName(tt.in); got != tt.want {
+			t.Errorf("goName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPathName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/pets", "Pets"},
+		{"/pets/{petId}", "PetsByPetID"},
+		{"/pets/{petId}/photos", "PetsByPetIDPhotos"},
+		{"/user-groups/{group.id}", "UserGroupsByGroupID"},
+		{"/", ""},
+	}
+	for _, tt := range tests {
+		if got := pathName(tt.path); got != tt.want {
+			t.Errorf("pathName(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRoutePaths(t *testing.T) {
+	tests := []struct {
+		path      string
+		wantColon string
+		wantBrace string
+	}{
+		{"/pets", "/pets", "/pets"},
+		{"/pets/{petId}", "/pets/:petId", "/pets/{petId}"},
+		{"/users/{user-id}/keys/{key.name}", "/users/:user_id/keys/:key_name", "/users/{user_id}/keys/{key_name}"},
+	}
+	for _, tt := range tests {
+		colon, brace := routePaths(tt.path)
+		if colon != tt.wantColon || brace != tt.wantBrace {
+			t.Errorf("routePaths(%q) = %q, %q; want %q, %q", tt.path, colon, brace, tt.wantColon, tt.wantBrace)
+		}
+	}
+}
+
+func TestOneLine(t *testing.T) {
+	if got := oneLine("  A pet\n  in the\tstore.\n"); got != "A pet in the store." {
+		t.Errorf("oneLine() = %q", got)
+	}
+}
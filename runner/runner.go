@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type Runner interface {
+	Run(dir, name string, args ...string) error
+	Output(dir, name string, args ...string) ([]byte, error)
+	LookPath(name string) (string, error)
+}
+
+type ExecRunner struct{}
+
+func NewExecRunner() *ExecRunner {
+	return &ExecRunner{}
+}
+
+func (r *ExecRunner) Run(dir, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func (r *ExecRunner) Output(dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return out, fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+		}
+		return out, fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, msg)
+	}
+	return out, nil
+}
+
+func (r *ExecRunner) LookPath(name string) (string, error) {
+	return exec.LookPath(name)
+}
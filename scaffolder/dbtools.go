@@ -0,0 +1,59 @@
+package scaffolder
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/SwanHtetAungPhyo/gostart/templates"
+	"github.com/fatih/color"
+)
+
+// dbTools maps the database tools offered in the dependency list to the
+// command each one pins as a tool directive.
+var dbTools = map[string]string{
+	"github.com/golang-migrate/migrate/v4": "github.com/golang-migrate/migrate/v4/cmd/migrate",
+	"github.com/sqlc-dev/sqlc":             "github.com/sqlc-dev/sqlc/cmd/sqlc",
+}
+
+// setupDatabaseTools lays out db/ for golang-migrate and sqlc and pins both
+// in go.mod, so the migrate-* and sqlc tasks run the versions recorded
+// there.
+func (s *Scaffolder) setupDatabaseTools() error {
+	files := make(map[string]string)
+	var tools []string
+	if s.config.UsesMigrations() {
+		files[filepath.Join(templates.MigrationsDir, ".gitkeep")] = ""
+		tools = append(tools, "github.com/golang-migrate/migrate/v4")
+	}
+	if s.config.UsesSQLC() {
+		generator := templates.TemplateGenerator{}
+		content, err := generator.GetSQLCTemplate(s.config)
+		if err != nil {
+			return err
+		}
+		files["sqlc.yaml"] = content
+		files["db/queries/.gitkeep"] = ""
+		if !s.config.UsesMigrations() {
+			files["db/schema.sql"] = "-- The tables sqlc checks the queries in db/queries against.\n"
+		}
+		tools = append(tools, "github.com/sqlc-dev/sqlc")
+	}
+	if len(tools) == 0 {
+		return nil
+	}
+	if err := s.writeFiles(files); err != nil {
+		return err
+	}
+
+	for _, module := range tools {
+		if err := s.runCommand("go", "get", module+"@latest"); err != nil {
+			return fmt.Errorf("failed to add %s: %w", module, err)
+		}
+		if err := s.runCommand("go", "mod", "edit", "-tool="+dbTools[module]); err != nil {
+			return fmt.Errorf("failed to add %s as a tool: %w", dbTools[module], err)
+		}
+	}
+
+	color.Green("✅ Database tools pinned in go.mod")
+	return nil
+}
@@ -0,0 +1,80 @@
+package scaffolder
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+)
+
+// command is one call a fakeRunner received.
+type command struct {
+	Dir  string
+	Line string
+}
+
+// fakeRunner records commands instead of running them. A command fails
+// when its line starts with a key of fail, and LookPath only finds the
+// tools listed in installed.
+type fakeRunner struct {
+	calls     []command
+	fail      map[string]error
+	installed []string
+}
+
+func (r *fakeRunner) Run(dir, name string, args ...string) error {
+	_, err := r.Output(dir, name, args...)
+	return err
+}
+
+func (r *fakeRunner) Output(dir, name string, args ...string) ([]byte, error) {
+	line := strings.Join(append([]string{name}, args...), " ")
+	r.calls = append(r.calls, command{Dir: dir, Line: line})
+	for prefix, err := range r.fail {
+		if strings.HasPrefix(line, prefix) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRunner) LookPath(name string) (string, error) {
+	for _, tool := range r.installed {
+		if tool == name {
+			return "/usr/local/bin/" + name, nil
+		}
+	}
+	return "", exec.ErrNotFound
+}
+
+func (r *fakeRunner) lines() []string {
+	lines := make([]string, len(r.calls))
+	for i, call := range r.calls {
+		lines[i] = call.Line
+	}
+	return lines
+}
+
+// newTestScaffolder scaffolds into a temporary directory through r.
+func newTestScaffolder(t *testing.T, cfg *config.Config, r *fakeRunner) *Scaffolder {
+	t.Helper()
+	cfg.ProjectDir = t.TempDir()
+	return NewScaffolderWithRunner(cfg, r)
+}
+
+func expectCommands(t *testing.T, r *fakeRunner, dir string, want []string) {
+	t.Helper()
+	got := r.lines()
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("commands:\n  %s\nwant:\n  %s", strings.Join(got, "\n  "), strings.Join(want, "\n  "))
+	}
+	for _, call := range r.calls {
+		if call.Dir != dir {
+			t.Errorf("%q ran in %q, want the project directory %q", call.Line, call.Dir, dir)
+		}
+	}
+}
+
+var errBoom = errors.New("boom")
@@ -0,0 +1,44 @@
+package scaffolder
+
+import (
+	"fmt"
+
+	"github.com/SwanHtetAungPhyo/gostart/templates"
+	"github.com/fatih/color"
+)
+
+const gqlgenModule = "github.com/99designs/gqlgen"
+
+func (s *Scaffolder) generateGraphQLSchema() error {
+	if s.config.AppType != "graphql" {
+		return nil
+	}
+
+	generator := templates.TemplateGenerator{}
+	files, err := generator.GetGraphQLTemplates(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(files)
+}
+
+// generateGraphQLCode runs gqlgen against the starter schema, producing
+// the executable schema and models the hand-written resolvers build on.
+func (s *Scaffolder) generateGraphQLCode() error {
+	if s.config.AppType != "graphql" {
+		return nil
+	}
+	s.generatedCodeMissing = true
+
+	if err := s.runCommand("go", "get", gqlgenModule); err != nil {
+		return fmt.Errorf("failed to add gqlgen: %w", err)
+	}
+	if err := s.runCommand("go", "run", gqlgenModule, "generate"); err != nil {
+		color.Yellow("💡 Fix the schema or resolvers, then run: go run %s generate && go mod tidy", gqlgenModule)
+		return fmt.Errorf("gqlgen generate failed: %w", err)
+	}
+
+	s.generatedCodeMissing = false
+	color.Green("✅ GraphQL code generated")
+	return nil
+}
@@ -0,0 +1,71 @@
+package scaffolder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+)
+
+func TestGenerateGraphQLCode(t *testing.T) {
+	tests := []struct {
+		name        string
+		fail        map[string]error
+		want        []string
+		wantMissing bool
+	}{
+		{
+			name: "generates",
+			want: []string{
+				"go get github.com/99designs/gqlgen",
+				"go run github.com/99designs/gqlgen generate",
+			},
+		},
+		{
+			name:        "go get fails",
+			fail:        map[string]error{"go get": errBoom},
+			want:        []string{"go get github.com/99designs/gqlgen"},
+			wantMissing: true,
+		},
+		{
+			name: "generate fails",
+			fail: map[string]error{"go run": errBoom},
+			want: []string{
+				"go get github.com/99designs/gqlgen",
+				"go run github.com/99designs/gqlgen generate",
+			},
+			wantMissing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRunner{fail: tt.fail}
+			s := newTestScaffolder(t, &config.Config{ModuleName: "example.com/acme/gql", AppType: "graphql"}, r)
+
+			err := s.generateGraphQLCode()
+			if tt.fail != nil {
+				if !errors.Is(err, errBoom) {
+					t.Errorf("error = %v, want it to wrap the runner's error", err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			expectCommands(t, r, s.config.ProjectDir, tt.want)
+			if s.generatedCodeMissing != tt.wantMissing {
+				t.Errorf("generatedCodeMissing = %v, want %v", s.generatedCodeMissing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestGenerateGraphQLCodeSkipsOtherAppTypes(t *testing.T) {
+	r := &fakeRunner{}
+	s := newTestScaffolder(t, &config.Config{ModuleName: "example.com/acme/web", AppType: "web"}, r)
+	if err := s.generateGraphQLCode(); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.calls) != 0 {
+		t.Errorf("ran %v for a web app, want nothing", r.lines())
+	}
+}
@@ -0,0 +1,94 @@
+package scaffolder
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/SwanHtetAungPhyo/gostart/templates"
+)
+
+// generatedCode matches the marker of files that tools regenerate, which
+// would lose a header added afterwards.
+var generatedCode = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+// generateLicense writes LICENSE for the chosen license, unless a template
+// pack already rendered one.
+func (s *Scaffolder) generateLicense() error {
+	if !s.config.UsesLicense() {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(s.config.ProjectDir, "LICENSE")); err == nil {
+		return nil
+	}
+	generator := templates.TemplateGenerator{}
+	content, err := generator.GetLicenseTemplate(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(map[string]string{"LICENSE": content})
+}
+
+// addLicenseReadmeSection appends the License section to the README a
+// template pack rendered, unless the pack already points at LICENSE.
+func (s *Scaffolder) addLicenseReadmeSection() error {
+	if !s.config.UsesLicense() {
+		return nil
+	}
+	path := filepath.Join(s.config.ProjectDir, "README.md")
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(content, []byte("LICENSE")) {
+		return nil
+	}
+
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		content = append(content, '\n')
+	}
+	generator := templates.TemplateGenerator{}
+	content = append(content, "\n"+generator.GetLicenseReadmeSection(s.config)...)
+	return os.WriteFile(path, content, 0644)
+}
+
+// addSPDXHeaders puts the copyright and SPDX-License-Identifier lines at the
+// top of every hand-written Go file in the project. Generated code, vendor/,
+// testdata and hidden directories are left alone, as are files that already
+// carry an identifier.
+func (s *Scaffolder) addSPDXHeaders() error {
+	generator := templates.TemplateGenerator{}
+	header := []byte(generator.GetSPDXHeader(s.config) + "\n")
+
+	root := s.config.ProjectDir
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			name := entry.Name()
+			if path != root && (name == "vendor" || name == "testdata" || name[0] == '.') {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if generatedCode.Match(content) || bytes.Contains(content, []byte("SPDX-License-Identifier:")) {
+			return nil
+		}
+		return os.WriteFile(path, append(header, content...), 0644)
+	})
+}
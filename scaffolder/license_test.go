@@ -0,0 +1,149 @@
+package scaffolder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+)
+
+func licenseConfig() *config.Config {
+	return &config.Config{
+		ModuleName:    "example.com/acme/kit",
+		AppType:       "library",
+		License:       "Apache-2.0",
+		LicenseAuthor: "Ada Lovelace",
+		LicenseYear:   "2026",
+		SPDXHeaders:   true,
+	}
+}
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestAddSPDXHeaders(t *testing.T) {
+	const header = "// SPDX-FileCopyrightText: 2026 Ada Lovelace\n// SPDX-License-Identifier: Apache-2.0\n\n"
+	const headed = "// SPDX-License-Identifier: MIT\n\npackage kit\n"
+	files := map[string]string{
+		"kit.go":                    "package kit\n",
+		"internal/util/util.go":     "package util\n",
+		"api/gen/greeter.pb.go":     "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage gen\n",
+		"internal/model/x.gen.go":   "// Code generated by gpm from api/openapi.yaml; DO NOT EDIT.\n\npackage model\n",
+		"vendor/example.com/d/d.go": "package d\n",
+		"testdata/fixture.go":       "package fixture\n",
+		".cache/tool.go":            "package tool\n",
+		"headed.go":                 headed,
+		"notes.txt":                 "notes\n",
+	}
+	want := map[string]string{
+		"kit.go":                "package kit\n",
+		"internal/util/util.go": "package util\n",
+	}
+
+	s := newTestScaffolder(t, licenseConfig(), &fakeRunner{})
+	writeTestFiles(t, s.config.ProjectDir, files)
+	if err := s.addSPDXHeaders(); err != nil {
+		t.Fatal(err)
+	}
+	for name, original := range files {
+		got := readTestFile(t, filepath.Join(s.config.ProjectDir, name))
+		if body, ok := want[name]; ok {
+			if got != header+body {
+				t.Errorf("%s =\n%s\nwant the header added", name, got)
+			}
+		} else if got != original {
+			t.Errorf("%s was changed:\n%s", name, got)
+		}
+	}
+}
+
+func TestGenerateLicense(t *testing.T) {
+	t.Run("writes LICENSE", func(t *testing.T) {
+		s := newTestScaffolder(t, licenseConfig(), &fakeRunner{})
+		if err := s.generateLicense(); err != nil {
+			t.Fatal(err)
+		}
+		if got := readTestFile(t, filepath.Join(s.config.ProjectDir, "LICENSE")); !strings.Contains(got, "Copyright 2026 Ada Lovelace") {
+			t.Errorf("LICENSE does not name the holder:\n%s", got)
+		}
+	})
+
+	t.Run("keeps a pack's LICENSE", func(t *testing.T) {
+		s := newTestScaffolder(t, licenseConfig(), &fakeRunner{})
+		writeTestFiles(t, s.config.ProjectDir, map[string]string{"LICENSE": "pack license\n"})
+		if err := s.generateLicense(); err != nil {
+			t.Fatal(err)
+		}
+		if got := readTestFile(t, filepath.Join(s.config.ProjectDir, "LICENSE")); got != "pack license\n" {
+			t.Errorf("LICENSE = %q, want the pack's", got)
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		cfg := licenseConfig()
+		cfg.License = "none"
+		s := newTestScaffolder(t, cfg, &fakeRunner{})
+		if err := s.generateLicense(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(s.config.ProjectDir, "LICENSE")); !os.IsNotExist(err) {
+			t.Errorf("LICENSE written without a license: %v", err)
+		}
+	})
+}
+
+func TestAddLicenseReadmeSection(t *testing.T) {
+	const section = "## License\n\nCopyright (c) 2026 Ada Lovelace. Released under the Apache License 2.0 (`Apache-2.0`); see [LICENSE](LICENSE).\n"
+	tests := []struct {
+		name   string
+		readme string
+		want   string
+	}{
+		{name: "appends", readme: "# kit\n", want: "# kit\n\n" + section},
+		{name: "adds a missing newline", readme: "# kit", want: "# kit\n\n" + section},
+		{name: "keeps a pack's reference", readme: "# kit\n\nSee LICENSE.\n", want: "# kit\n\nSee LICENSE.\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScaffolder(t, licenseConfig(), &fakeRunner{})
+			writeTestFiles(t, s.config.ProjectDir, map[string]string{"README.md": tt.readme})
+			if err := s.addLicenseReadmeSection(); err != nil {
+				t.Fatal(err)
+			}
+			if got := readTestFile(t, filepath.Join(s.config.ProjectDir, "README.md")); got != tt.want {
+				t.Errorf("README.md =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no README", func(t *testing.T) {
+		s := newTestScaffolder(t, licenseConfig(), &fakeRunner{})
+		if err := s.addLicenseReadmeSection(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(s.config.ProjectDir, "README.md")); !os.IsNotExist(err) {
+			t.Errorf("README.md created: %v", err)
+		}
+	})
+}
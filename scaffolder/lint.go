@@ -0,0 +1,40 @@
+package scaffolder
+
+import (
+	"fmt"
+
+	"github.com/SwanHtetAungPhyo/gostart/templates"
+	"github.com/fatih/color"
+)
+
+const golangciLintModule = "github.com/golangci/golangci-lint/v2"
+
+// setupLinter writes .golangci.yml for the chosen preset and pins
+// golangci-lint with a tool directive, so make lint and CI run the same
+// version through go tool golangci-lint. The latest release is resolved
+// once here, since older ones cannot read packages built by newer Go
+// toolchains; after that go.mod decides and upgrades are explicit.
+func (s *Scaffolder) setupLinter() error {
+	if !s.config.UsesLinter() {
+		return nil
+	}
+
+	generator := templates.TemplateGenerator{}
+	content, err := generator.GetLintTemplate(s.config)
+	if err != nil {
+		return err
+	}
+	if err := s.writeFiles(map[string]string{".golangci.yml": content}); err != nil {
+		return err
+	}
+
+	if err := s.runCommand("go", "get", golangciLintModule+"@latest"); err != nil {
+		return fmt.Errorf("failed to add golangci-lint: %w", err)
+	}
+	if err := s.runCommand("go", "mod", "edit", "-tool="+golangciLintModule+"/cmd/golangci-lint"); err != nil {
+		return fmt.Errorf("failed to add golangci-lint as a tool: %w", err)
+	}
+
+	color.Green("✅ golangci-lint pinned in go.mod with the %s preset", s.config.LintPreset)
+	return nil
+}
@@ -0,0 +1,161 @@
+package scaffolder
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+	"github.com/SwanHtetAungPhyo/gostart/openapi"
+	"github.com/SwanHtetAungPhyo/gostart/templates"
+	"github.com/fatih/color"
+)
+
+const (
+	swagModule       = "github.com/swaggo/swag"
+	kinOpenAPIModule = "github.com/getkin/kin-openapi"
+)
+
+// generateSwaggerSpec adds the module the spec test validates with and,
+// with swaggo, pins swag as a go tool and runs the go:generate directive
+// in api/, which writes the spec from the annotations.
+func (s *Scaffolder) generateSwaggerSpec() error {
+	if !s.config.HasAPIDocs() {
+		return nil
+	}
+	if err := s.runCommand("go", "get", kinOpenAPIModule); err != nil {
+		return fmt.Errorf("failed to add kin-openapi: %w", err)
+	}
+	if !s.config.UseSwaggo {
+		return nil
+	}
+	s.generatedCodeMissing = true
+
+	if err := s.runCommand("go", "get", swagModule); err != nil {
+		return fmt.Errorf("failed to add swag: %w", err)
+	}
+	if err := s.runCommand("go", "mod", "edit", "-tool="+swagModule+"/cmd/swag"); err != nil {
+		return fmt.Errorf("failed to add swag as a tool: %w", err)
+	}
+	// The api package cannot load until the spec it embeds exists, so
+	// record swag's dependencies without failing on it.
+	if err := s.runCommand("go", "mod", "tidy", "-e"); err != nil {
+		return fmt.Errorf("failed to resolve swag dependencies: %w", err)
+	}
+	if err := s.runCommand("go", "generate", "./api"); err != nil {
+		color.Yellow("💡 Fix the annotations, then run: %s", cmp.Or(s.config.TaskCommand("spec"), "go generate ./api"))
+		return fmt.Errorf("swag init failed: %w", err)
+	}
+
+	s.generatedCodeMissing = false
+	color.Green("✅ OpenAPI spec generated from swaggo annotations")
+	return nil
+}
+
+func (s *Scaffolder) generateOpenAPIHandlers() error {
+	if s.config.OpenAPISpec == "" {
+		return nil
+	}
+	return s.GenerateOpenAPI(s.config.OpenAPISpec, "api/openapi.yaml")
+}
+
+// GenerateOpenAPI writes the models, handler interface, validation and
+// routes for the document at specPath; source is how the generated files
+// refer to it. The *.gen.go files are rewritten on every run, while
+// internal/handler/api.go is only created when it does not exist yet.
+func (s *Scaffolder) GenerateOpenAPI(specPath, source string) error {
+	doc, err := openapi.Load(specPath)
+	if err != nil {
+		return err
+	}
+	api, err := openapi.Build(doc, source)
+	if err != nil {
+		return fmt.Errorf("failed to map %s: %w", source, err)
+	}
+
+	generator := templates.TemplateGenerator{}
+	generated, stubs, err := generator.GetOpenAPITemplates(s.config, api)
+	if err != nil {
+		return err
+	}
+	if err := s.writeFiles(generated); err != nil {
+		return err
+	}
+	for name, content := range stubs {
+		if _, err := os.Stat(filepath.Join(s.config.ProjectDir, name)); err == nil {
+			continue
+		}
+		if err := s.writeFiles(map[string]string{name: content}); err != nil {
+			return err
+		}
+	}
+
+	for _, skipped := range api.Skipped {
+		color.Yellow("⚠️  Skipped %s", skipped)
+	}
+	color.Green("✅ Generated %d operations from %s", len(api.Endpoints), source)
+	return nil
+}
+
+// frameworkModules lists the module that gives each web framework away in
+// an existing project's go.mod.
+var frameworkModules = []struct {
+	framework string
+	module    string
+}{
+	{"gin", "github.com/gin-gonic/gin"},
+	{"echo", "github.com/labstack/echo/v4"},
+	{"fiber", "github.com/gofiber/fiber/v2"},
+	{"chi", "github.com/go-chi/chi/v5"},
+	{"gorilla", "github.com/gorilla/mux"},
+}
+
+// ProjectConfig rebuilds enough of a web project's configuration from its
+// go.mod to regenerate code in it. The framework comes from the direct
+// requirements; indirect ones are pulled in by other modules.
+func ProjectConfig(dir string) (*config.Config, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod (run this from the project root): %w", err)
+	}
+
+	cfg := &config.Config{AppType: "web", Framework: "stdlib", ProjectDir: dir}
+	required := map[string]bool{}
+	inRequire := false
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case fields[0] == "module" && len(fields) >= 2:
+			cfg.ModuleName = fields[1]
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequire = true
+		case inRequire && fields[0] == ")":
+			inRequire = false
+		case strings.Contains(line, "// indirect"):
+		case inRequire:
+			required[fields[0]] = true
+		case fields[0] == "require" && len(fields) >= 2:
+			required[fields[1]] = true
+		}
+	}
+
+	var found []string
+	for _, fm := range frameworkModules {
+		if required[fm.module] {
+			found = append(found, fm.framework)
+		}
+	}
+	switch {
+	case len(found) > 1:
+		return nil, fmt.Errorf("go.mod requires %s; cannot tell which one serves the API", strings.Join(found, " and "))
+	case len(found) == 1:
+		cfg.Framework = found[0]
+	}
+	if cfg.ModuleName == "" {
+		return nil, fmt.Errorf("no module directive in %s", filepath.Join(dir, "go.mod"))
+	}
+	return cfg, nil
+}
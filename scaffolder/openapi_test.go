@@ -0,0 +1,79 @@
+package scaffolder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProjectConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		gomod   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "no framework",
+			gomod: "module example.com/acme/api\n\ngo 1.24\n",
+			want:  "stdlib",
+		},
+		{
+			name:  "single-line require",
+			gomod: "module example.com/acme/api\n\nrequire github.com/labstack/echo/v4 v4.13.3\n",
+			want:  "echo",
+		},
+		{
+			name: "indirect requires are ignored",
+			gomod: "module example.com/acme/api\n\nrequire (\n" +
+				"\tgithub.com/go-chi/chi/v5 v5.2.1\n" +
+				"\tgithub.com/gorilla/mux v1.8.1 // indirect\n" +
+				")\n",
+			want: "chi",
+		},
+		{
+			name: "replace lines are ignored",
+			gomod: "module example.com/acme/api\n\nrequire github.com/gin-gonic/gin v1.10.0\n\n" +
+				"replace github.com/gorilla/mux => ../mux\n",
+			want: "gin",
+		},
+		{
+			name: "two direct frameworks",
+			gomod: "module example.com/acme/api\n\nrequire (\n" +
+				"\tgithub.com/gin-gonic/gin v1.10.0\n" +
+				"\tgithub.com/gorilla/mux v1.8.1\n" +
+				")\n",
+			wantErr: "go.mod requires gin and gorilla",
+		},
+		{
+			name:    "no module directive",
+			gomod:   "go 1.24\n",
+			wantErr: "no module directive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(tt.gomod), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := ProjectConfig(dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Framework != tt.want {
+				t.Errorf("Framework = %q, want %q", cfg.Framework, tt.want)
+			}
+			if cfg.ModuleName != "example.com/acme/api" {
+				t.Errorf("ModuleName = %q", cfg.ModuleName)
+			}
+		})
+	}
+}
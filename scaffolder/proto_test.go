@@ -0,0 +1,104 @@
+package scaffolder
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+)
+
+func TestGenerateProtoStubs(t *testing.T) {
+	setup := []string{
+		"go get google.golang.org/grpc google.golang.org/protobuf google.golang.org/grpc/cmd/protoc-gen-go-grpc",
+		"go mod edit -tool=google.golang.org/protobuf/cmd/protoc-gen-go -tool=google.golang.org/grpc/cmd/protoc-gen-go-grpc",
+	}
+	protoc := []string{
+		"go build -o bin/ google.golang.org/protobuf/cmd/protoc-gen-go google.golang.org/grpc/cmd/protoc-gen-go-grpc",
+		"protoc -I api/proto" +
+			" --plugin=protoc-gen-go=bin/protoc-gen-go --go_out=api/gen --go_opt=paths=source_relative" +
+			" --plugin=protoc-gen-go-grpc=bin/protoc-gen-go-grpc --go-grpc_out=api/gen --go-grpc_opt=paths=source_relative" +
+			" greeter/v1/greeter.proto",
+	}
+
+	tests := []struct {
+		name        string
+		installed   []string
+		fail        map[string]error
+		want        []string
+		wantErr     bool
+		wantMissing bool
+	}{
+		{
+			name:      "buf",
+			installed: []string{"buf", "protoc"},
+			want:      append(setup, "buf generate"),
+		},
+		{
+			name:      "protoc without buf",
+			installed: []string{"protoc"},
+			want:      append(setup, protoc...),
+		},
+		{
+			name:        "neither tool",
+			want:        setup,
+			wantMissing: true,
+		},
+		{
+			name:        "buf fails",
+			installed:   []string{"buf"},
+			fail:        map[string]error{"buf": errBoom},
+			want:        append(setup, "buf generate"),
+			wantErr:     true,
+			wantMissing: true,
+		},
+		{
+			name:        "protoc fails",
+			installed:   []string{"protoc"},
+			fail:        map[string]error{"protoc": errBoom},
+			want:        append(setup, protoc...),
+			wantErr:     true,
+			wantMissing: true,
+		},
+		{
+			name:        "go get fails",
+			installed:   []string{"buf"},
+			fail:        map[string]error{"go get": errBoom},
+			want:        setup[:1],
+			wantErr:     true,
+			wantMissing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRunner{installed: tt.installed, fail: tt.fail}
+			s := newTestScaffolder(t, &config.Config{ModuleName: "example.com/acme/greeter", AppType: "grpc"}, r)
+
+			err := s.generateProtoStubs()
+			if tt.wantErr {
+				if !errors.Is(err, errBoom) {
+					t.Errorf("error = %v, want it to wrap the runner's error", err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			expectCommands(t, r, s.config.ProjectDir, tt.want)
+			if s.generatedCodeMissing != tt.wantMissing {
+				t.Errorf("generatedCodeMissing = %v, want %v", s.generatedCodeMissing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestRunProtocCreatesOutputDirectory(t *testing.T) {
+	r := &fakeRunner{installed: []string{"protoc"}}
+	s := newTestScaffolder(t, &config.Config{ModuleName: "example.com/acme/greeter", AppType: "grpc"}, r)
+	if err := s.generateProtoStubs(); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(filepath.Join(s.config.ProjectDir, "api", "gen")); err != nil || !info.IsDir() {
+		t.Errorf("api/gen was not created before protoc ran: %v", err)
+	}
+}
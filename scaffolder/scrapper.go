@@ -3,6 +3,7 @@ package scaffolder
 import (
 	"fmt"
 	"github.com/SwanHtetAungPhyo/gostart/config"
+	"github.com/SwanHtetAungPhyo/gostart/runner"
 	"github.com/SwanHtetAungPhyo/gostart/spinner"
 	"github.com/SwanHtetAungPhyo/gostart/templates"
 	"time"
@@ -18,12 +19,22 @@ import (
 type Scaffolder struct {
 	config  *config.Config
 	spinner *spinner.Spinner
+	runner  runner.Runner
+
+	// generatedCodeMissing is set when stubs the generated sources import
+	// (protobuf, gqlgen) could not be produced during scaffolding.
+	generatedCodeMissing bool
 }
 
 func NewScaffolder(config *config.Config) *Scaffolder {
+	return NewScaffolderWithRunner(config, runner.NewExecRunner())
+}
+
+func NewScaffolderWithRunner(config *config.Config, r runner.Runner) *Scaffolder {
 	return &Scaffolder{
 		config:  config,
 		spinner: spinner.NewSpinner(),
+		runner:  r,
 	}
 }
 func (s *Scaffolder) createDirectoryStructure() error {
@@ -35,18 +46,34 @@ func (s *Scaffolder) initializeGoModule() error {
 }
 
 func (s *Scaffolder) generateMainFile() error {
+	if s.config.IsLibrary() {
+		generator := templates.TemplateGenerator{}
+		files, err := generator.GetLibraryTemplates(s.config)
+		if err != nil {
+			return err
+		}
+		return s.writeFiles(files)
+	}
+
 	cmdDir := filepath.Join(s.config.ProjectDir, "cmd")
 	if err := os.MkdirAll(cmdDir, 0755); err != nil {
 		return err
 	}
 
 	generator := templates.TemplateGenerator{}
-	mainContent := generator.GetMainTemplate(s.config.AppType, s.config.Framework)
+	mainContent, err := generator.GetMainTemplate(s.config)
+	if err != nil {
+		return err
+	}
 	mainPath := filepath.Join(cmdDir, "main.go")
 	return os.WriteFile(mainPath, []byte(mainContent), 0644)
 }
 
 func (s *Scaffolder) createInternalStructure() error {
+	if s.config.IsLibrary() {
+		return nil
+	}
+
 	dirs := []string{
 		"internal/model",
 		"internal/repository",
@@ -63,6 +90,9 @@ func (s *Scaffolder) createInternalStructure() error {
 		if err := os.MkdirAll(fullPath, 0755); err != nil {
 			return err
 		}
+		if entries, err := os.ReadDir(fullPath); err == nil && len(entries) > 0 {
+			continue
+		}
 		readmePath := filepath.Join(fullPath, ".gitkeep")
 		err := os.WriteFile(readmePath, []byte(""), 0644)
 		if err != nil {
@@ -74,11 +104,188 @@ func (s *Scaffolder) createInternalStructure() error {
 	return nil
 }
 
+func (s *Scaffolder) generateExampleResource() error {
+	// The todo slice plugs into the web router; other app types ignore it.
+	if !s.config.ExampleResource || !s.config.IsWeb() {
+		return nil
+	}
+
+	generator := templates.TemplateGenerator{}
+	files, err := generator.GetExampleResourceTemplates(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(files)
+}
+
+func (s *Scaffolder) generateRouter() error {
+	if s.config.AppType != "web" && s.config.AppType != "graphql" {
+		return nil
+	}
+
+	generator := templates.TemplateGenerator{}
+	files, err := generator.GetRouterTemplates(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(files)
+}
+
+func (s *Scaffolder) generateAPIDocs() error {
+	if !s.config.HasAPIDocs() {
+		return nil
+	}
+
+	generator := templates.TemplateGenerator{}
+	files, err := generator.GetAPIDocsTemplates(s.config)
+	if err != nil {
+		return err
+	}
+	if s.config.OpenAPISpec != "" {
+		spec, err := os.ReadFile(s.config.OpenAPISpec)
+		if err != nil {
+			return fmt.Errorf("failed to read OpenAPI document: %w", err)
+		}
+		files["api/openapi.yaml"] = string(spec)
+	}
+	return s.writeFiles(files)
+}
+
+func (s *Scaffolder) generateCobraCommands() error {
+	if s.config.AppType != "cobra" {
+		return nil
+	}
+
+	generator := templates.TemplateGenerator{}
+	files, err := generator.GetCobraCommandTemplates(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(files)
+}
+
+func (s *Scaffolder) generateGRPCService() error {
+	if s.config.AppType != "grpc" {
+		return nil
+	}
+
+	generator := templates.TemplateGenerator{}
+	files, err := generator.GetGRPCTemplates(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(files)
+}
+
+func (s *Scaffolder) generateWorker() error {
+	if s.config.AppType != "worker" {
+		return nil
+	}
+
+	generator := templates.TemplateGenerator{}
+	files, err := generator.GetWorkerTemplates(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(files)
+}
+
+func (s *Scaffolder) generateConfigPackage() error {
+	if !s.config.IsService() {
+		return nil
+	}
+
+	generator := templates.TemplateGenerator{}
+	content, err := generator.GetConfigTemplate(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(map[string]string{"internal/config/config.go": content})
+}
+
+func (s *Scaffolder) generateLoggerPackage() error {
+	if !s.config.IsService() {
+		return nil
+	}
+
+	generator := templates.TemplateGenerator{}
+	files, err := generator.GetLoggerTemplates(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(files)
+}
+
+func (s *Scaffolder) generateStore() error {
+	if !s.config.IsService() {
+		return nil
+	}
+	if s.config.HasDependency("gorm.io/gorm") && s.config.Database() == "" {
+		color.Yellow("⚠️  GORM was selected without a driver; skipping database wiring")
+	}
+	if !s.config.UsesStore() {
+		return nil
+	}
+
+	generator := templates.TemplateGenerator{}
+	files, err := generator.GetStoreTemplates(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(files)
+}
+
+func (s *Scaffolder) generateServerPackage() error {
+	if !s.config.IsService() {
+		return nil
+	}
+
+	generator := templates.TemplateGenerator{}
+	content, err := generator.GetServerTemplate(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(map[string]string{"internal/server/server.go": content})
+}
+
+func (s *Scaffolder) writeFiles(files map[string]string) error {
+	for name, content := range files {
+		path := filepath.Join(s.config.ProjectDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *Scaffolder) generateDockerfile() error {
-	dockerPath := filepath.Join(s.config.ProjectDir, "Dockerfile")
 	generator := templates.TemplateGenerator{}
-	content := generator.GetDockerTemplate()
-	return os.WriteFile(dockerPath, []byte(content), 0644)
+	files, err := generator.GetDockerTemplates(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(files)
+}
+
+func (s *Scaffolder) generateCompose() error {
+	generator := templates.TemplateGenerator{}
+	content, err := generator.GetComposeTemplate(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(map[string]string{"compose.yaml": content})
+}
+
+func (s *Scaffolder) generateDeploy() error {
+	generator := templates.TemplateGenerator{}
+	files, err := generator.GetDeployTemplates(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(files)
 }
 
 func (s *Scaffolder) setupAir() error {
@@ -133,22 +340,70 @@ func (s *Scaffolder) generateAirConfig() error {
 	return nil
 }
 
-func (s *Scaffolder) generateMakefile() error {
-	makefilePath := filepath.Join(s.config.ProjectDir, "Makefile")
+func (s *Scaffolder) generateTaskRunner() error {
+	generator := templates.TemplateGenerator{}
+	files, err := generator.GetTaskRunnerTemplates(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(files)
+}
+
+func (s *Scaffolder) generateCI() error {
 	generator := templates.TemplateGenerator{}
-	content := generator.GetMakefileTemplate()
-	return os.WriteFile(makefilePath, []byte(content), 0644)
+	files, err := generator.GetCITemplates(s.config)
+	if err != nil {
+		return err
+	}
+	if err := s.writeFiles(files); err != nil {
+		return err
+	}
+	if _, ok := files["scripts/ci.sh"]; ok {
+		return os.Chmod(filepath.Join(s.config.ProjectDir, "scripts/ci.sh"), 0755)
+	}
+	return nil
 }
 
 func (s *Scaffolder) generateGitignore() error {
-	gitignorePath := filepath.Join(s.config.ProjectDir, ".gitignore")
 	generator := templates.TemplateGenerator{}
-	content := generator.GetGitignoreTemplate()
-	return os.WriteFile(gitignorePath, []byte(content), 0644)
+	content, err := generator.GetGitignoreTemplate(s.config)
+	if err != nil {
+		return err
+	}
+	files := map[string]string{".gitignore": content}
+	if s.config.UseGitattributes {
+		attributes, err := generator.GetGitattributesTemplate(s.config)
+		if err != nil {
+			return err
+		}
+		files[".gitattributes"] = attributes
+	}
+	return s.writeFiles(files)
+}
+
+// vendorDependencies copies the dependencies into vendor/, which the
+// generated .gitignore then leaves to be committed.
+func (s *Scaffolder) vendorDependencies() error {
+	cmd := exec.Command("go", "mod", "vendor")
+	cmd.Dir = s.config.ProjectDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to vendor dependencies: %w", err)
+	}
+
+	color.Green("✅ Dependencies vendored into vendor/")
+	return nil
 }
 
 func (s *Scaffolder) tidyGoMod() error {
-	cmd := exec.Command("go", "mod", "tidy")
+	args := []string{"mod", "tidy"}
+	if s.generatedCodeMissing {
+		// The generated packages the server imports do not exist yet.
+		args = append(args, "-e")
+	}
+	cmd := exec.Command("go", args...)
 	cmd.Dir = s.config.ProjectDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -162,6 +417,10 @@ func (s *Scaffolder) tidyGoMod() error {
 }
 
 func (s *Scaffolder) CreateProject() error {
+	if s.config.TemplateDir != "" {
+		return s.createFromTemplatePack()
+	}
+
 	color.Cyan("\n🚀 Scaffolding %s...", s.config.ModuleName)
 	s.spinner.Start()
 	defer s.spinner.Stop()
@@ -173,6 +432,18 @@ func (s *Scaffolder) CreateProject() error {
 		{"creating directory structure", s.createDirectoryStructure},
 		{"initializing Go module", s.initializeGoModule},
 		{"generating main file", s.generateMainFile},
+		{"generating cobra commands", s.generateCobraCommands},
+		{"generating gRPC service", s.generateGRPCService},
+		{"generating GraphQL schema", s.generateGraphQLSchema},
+		{"generating worker", s.generateWorker},
+		{"generating config package", s.generateConfigPackage},
+		{"generating logger package", s.generateLoggerPackage},
+		{"generating server package", s.generateServerPackage},
+		{"generating router", s.generateRouter},
+		{"generating API docs", s.generateAPIDocs},
+		{"generating OpenAPI handlers", s.generateOpenAPIHandlers},
+		{"generating database wiring", s.generateStore},
+		{"generating example resource", s.generateExampleResource},
 		{"creating internal structure", s.createInternalStructure},
 	}
 
@@ -188,8 +459,11 @@ func (s *Scaffolder) CreateProject() error {
 		fn      func() error
 	}{
 		{s.config.UseDocker, "generating Dockerfile", s.generateDockerfile},
+		{s.config.HasCompose(), "generating compose.yaml", s.generateCompose},
 		{s.config.UseAir, "setting up Air", s.setupAir},
-		{s.config.UseMakefile, "generating Makefile", s.generateMakefile},
+		{s.config.UsesDeploy(), "generating deploy manifests", s.generateDeploy},
+		{s.config.UsesTaskRunner(), "generating task runner file", s.generateTaskRunner},
+		{s.config.CI != "" && s.config.CI != "none", "generating CI pipeline", s.generateCI},
 	}
 
 	for _, step := range optionalSteps {
@@ -205,8 +479,14 @@ func (s *Scaffolder) CreateProject() error {
 		fn   func() error
 	}{
 		{"generating .gitignore", s.generateGitignore},
-		{"creating .env file", s.envFileCreation},
+		{"creating .env files", s.envFileCreation},
 		{"creating README.md", s.readmeFileCreation},
+		{"generating LICENSE", s.generateLicense},
+		{"generating gRPC stubs", s.generateProtoStubs},
+		{"generating GraphQL code", s.generateGraphQLCode},
+		{"generating OpenAPI spec", s.generateSwaggerSpec},
+		{"setting up golangci-lint", s.setupLinter},
+		{"setting up database tools", s.setupDatabaseTools},
 		{"tidying go.mod", s.tidyGoMod},
 	}
 
@@ -218,24 +498,45 @@ func (s *Scaffolder) CreateProject() error {
 	if err := s.installDependencies(); err != nil {
 		return fmt.Errorf("failed to install dependencies: %w", err)
 	}
+	if s.config.UseVendor {
+		if err := s.vendorDependencies(); err != nil {
+			color.Yellow("⚠️  vendoring dependencies failed: %v", err)
+		}
+	}
+	if s.config.UsesLicense() && s.config.SPDXHeaders {
+		if err := s.addSPDXHeaders(); err != nil {
+			color.Yellow("⚠️  adding SPDX headers failed: %v", err)
+		}
+	}
 
 	return nil
 }
 
 func (s *Scaffolder) runCommand(name string, args ...string) error {
-	cmd := exec.Command(name, args...)
-	cmd.Dir = s.config.ProjectDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return s.runner.Run(s.config.ProjectDir, name, args...)
 }
 
 func (s *Scaffolder) envFileCreation() error {
-	envPath := filepath.Join(s.config.ProjectDir, ".env")
-	content := `# Environment variables
+	if s.config.IsLibrary() {
+		return nil
+	}
+	if !s.config.IsService() {
+		envPath := filepath.Join(s.config.ProjectDir, ".env")
+		content := `# Environment variables
 # Add your environment variables here
 `
-	return os.WriteFile(envPath, []byte(content), 0644)
+		return os.WriteFile(envPath, []byte(content), 0644)
+	}
+
+	generator := templates.TemplateGenerator{}
+	content, err := generator.GetEnvExampleTemplate(s.config)
+	if err != nil {
+		return err
+	}
+	return s.writeFiles(map[string]string{
+		".env":         content,
+		".env.example": content,
+	})
 }
 
 func (s *Scaffolder) readmeFileCreation() error {
@@ -254,6 +555,10 @@ This is a Go project scaffolded by the Go Scaffolder (GPM)
 - **Dockerfile**: Contains Docker build instructions.
 - **.gitignore**: Specifies scaffolder to ignore in Git.
 `, s.config.ModuleName)
+	if s.config.UsesLicense() {
+		generator := templates.TemplateGenerator{}
+		content += "\n" + generator.GetLicenseReadmeSection(s.config)
+	}
 	return os.WriteFile(readmePath, []byte(content), 0644)
 }
 
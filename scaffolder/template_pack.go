@@ -0,0 +1,74 @@
+package scaffolder
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/SwanHtetAungPhyo/gostart/templatepack"
+	"github.com/fatih/color"
+)
+
+func (s *Scaffolder) createFromTemplatePack() error {
+	pack, err := templatepack.Load(s.config.TemplateDir)
+	if err != nil {
+		return err
+	}
+
+	color.Cyan("\n🚀 Scaffolding %s from %s...", s.config.ModuleName, pack.Name())
+	s.spinner.Start()
+	defer s.spinner.Stop()
+
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{"creating directory structure", s.createDirectoryStructure},
+		{"rendering template " + pack.Name(), func() error {
+			return pack.Render(s.config.ProjectDir, s.templateData())
+		}},
+		{"initializing Go module", s.initializeGoModuleIfMissing},
+		{"generating LICENSE", s.generateLicense},
+		{"adding the License section to README.md", s.addLicenseReadmeSection},
+	}
+
+	for _, step := range steps {
+		if err := step.fn(); err != nil {
+			return fmt.Errorf("failed %s: %w", step.name, err)
+		}
+	}
+
+	if err := s.tidyGoMod(); err != nil {
+		color.Yellow("⚠️  tidying go.mod failed: %v", err)
+	}
+	if err := s.installDependencies(); err != nil {
+		return fmt.Errorf("failed to install dependencies: %w", err)
+	}
+	if s.config.UsesLicense() && s.config.SPDXHeaders {
+		if err := s.addSPDXHeaders(); err != nil {
+			color.Yellow("⚠️  adding SPDX headers failed: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Scaffolder) templateData() map[string]any {
+	data := templatepack.BaseData(s.config.ModuleName, filepath.Base(s.config.ProjectDir))
+	if s.config.UsesLicense() {
+		data["license"] = s.config.SPDXIdentifier()
+		data["license_author"] = s.config.LicenseAuthor
+		data["license_year"] = s.config.LicenseYear
+	}
+	for name, value := range s.config.TemplateVars {
+		data[name] = value
+	}
+	return data
+}
+
+func (s *Scaffolder) initializeGoModuleIfMissing() error {
+	if _, err := os.Stat(filepath.Join(s.config.ProjectDir, "go.mod")); err == nil {
+		return nil
+	}
+	return s.initializeGoModule()
+}
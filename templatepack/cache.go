@@ -0,0 +1,175 @@
+package templatepack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/SwanHtetAungPhyo/gostart/runner"
+)
+
+// Cache keeps one bare mirror per repository under repos/ and one checked
+// out tree per commit under packs/, so a given commit is only extracted once.
+type Cache struct {
+	Dir    string
+	runner runner.Runner
+}
+
+func NewCache(r runner.Runner) (*Cache, error) {
+	dir := os.Getenv("GPM_CACHE_DIR")
+	if dir == "" {
+		userCache, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate user cache directory: %w", err)
+		}
+		dir = filepath.Join(userCache, "gpm")
+	}
+
+	return &Cache{
+		Dir:    filepath.Join(dir, "templates"),
+		runner: r,
+	}, nil
+}
+
+func (c *Cache) Fetch(src *Source) (*Pack, error) {
+	if src.Path != "" {
+		pack, err := Load(src.Path)
+		if err != nil {
+			return nil, err
+		}
+		pack.Source = src.Raw
+		return pack, nil
+	}
+
+	mirror, err := c.ensureMirror(src.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := c.resolve(mirror, src.Ref)
+	if err != nil {
+		if fetchErr := c.fetch(mirror); fetchErr != nil {
+			return nil, fetchErr
+		}
+		if commit, err = c.resolve(mirror, src.Ref); err != nil {
+			return nil, fmt.Errorf("ref %q not found in %s", src.Ref, src.URL)
+		}
+	}
+
+	dir, err := c.checkout(mirror, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	pack, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	pack.Source = src.Raw
+	pack.Commit = commit
+	return pack, nil
+}
+
+// Update fetches every cached mirror and returns the URLs it refreshed.
+// Packs are keyed by commit, so the next Fetch of a moved ref picks up
+// the new commit without touching older checkouts.
+func (c *Cache) Update() ([]string, error) {
+	entries, err := os.ReadDir(c.reposDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read template cache: %w", err)
+	}
+
+	var updated []string
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasSuffix(entry.Name(), ".git") {
+			continue
+		}
+		mirror := filepath.Join(c.reposDir(), entry.Name())
+		if err := c.fetch(mirror); err != nil {
+			return updated, err
+		}
+
+		url, err := c.runner.Output("", "git", "--git-dir", mirror, "config", "--get", "remote.origin.url")
+		if err != nil {
+			return updated, fmt.Errorf("failed to read remote of %s: %w", mirror, err)
+		}
+		updated = append(updated, strings.TrimSpace(string(url)))
+	}
+	return updated, nil
+}
+
+func (c *Cache) reposDir() string {
+	return filepath.Join(c.Dir, "repos")
+}
+
+func (c *Cache) packsDir() string {
+	return filepath.Join(c.Dir, "packs")
+}
+
+func (c *Cache) ensureMirror(url string) (string, error) {
+	sum := sha256.Sum256([]byte(url))
+	mirror := filepath.Join(c.reposDir(), hex.EncodeToString(sum[:8])+".git")
+	if _, err := os.Stat(mirror); err == nil {
+		return mirror, nil
+	}
+
+	if err := os.MkdirAll(c.reposDir(), 0755); err != nil {
+		return "", fmt.Errorf("failed to create template cache: %w", err)
+	}
+	if _, err := c.runner.Output("", "git", "clone", "--quiet", "--mirror", "--", url, mirror); err != nil {
+		os.RemoveAll(mirror)
+		return "", fmt.Errorf("failed to clone %s: %w", url, err)
+	}
+	return mirror, nil
+}
+
+func (c *Cache) fetch(mirror string) error {
+	if _, err := c.runner.Output("", "git", "--git-dir", mirror, "fetch", "--quiet", "--prune", "origin"); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", mirror, err)
+	}
+	return nil
+}
+
+func (c *Cache) resolve(mirror, ref string) (string, error) {
+	out, err := c.runner.Output("", "git", "--git-dir", mirror, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (c *Cache) checkout(mirror, commit string) (string, error) {
+	dir := filepath.Join(c.packsDir(), commit)
+	if _, err := os.Stat(dir); err == nil {
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(c.packsDir(), 0755); err != nil {
+		return "", fmt.Errorf("failed to create template cache: %w", err)
+	}
+	tmp, err := os.MkdirTemp(c.packsDir(), ".checkout-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create checkout directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if _, err := c.runner.Output("", "git", "clone", "--quiet", "--no-checkout", mirror, tmp); err != nil {
+		return "", fmt.Errorf("failed to check out %s: %w", commit, err)
+	}
+	if _, err := c.runner.Output(tmp, "git", "checkout", "--quiet", "--detach", commit); err != nil {
+		return "", fmt.Errorf("failed to check out %s: %w", commit, err)
+	}
+	if err := os.RemoveAll(filepath.Join(tmp, ".git")); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return "", fmt.Errorf("failed to store checkout %s: %w", commit, err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,314 @@
+package templatepack
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/gostart/runner"
+)
+
+// countingRunner runs commands for real and records the git subcommands.
+type countingRunner struct {
+	runner.Runner
+	calls []string
+}
+
+func (r *countingRunner) Output(dir, name string, args ...string) ([]byte, error) {
+	r.calls = append(r.calls, gitSubcommand(args))
+	return r.Runner.Output(dir, name, args...)
+}
+
+func (r *countingRunner) count(subcommand string) int {
+	n := 0
+	for _, call := range r.calls {
+		if call == subcommand {
+			n++
+		}
+	}
+	return n
+}
+
+// gitSubcommand skips the --git-dir option that precedes it.
+func gitSubcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--git-dir" {
+			i++
+			continue
+		}
+		return args[i]
+	}
+	return ""
+}
+
+// templateRepo is a working repository with a minimal pack, pushed to a
+// bare repository that the cache clones from.
+type templateRepo struct {
+	t    *testing.T
+	work string
+	bare string
+}
+
+func newTemplateRepo(t *testing.T) *templateRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	root := t.TempDir()
+	repo := &templateRepo{t: t, work: filepath.Join(root, "work"), bare: filepath.Join(root, "kit.git")}
+	repo.git(root, "init", "--quiet", "--bare", "--initial-branch=main", repo.bare)
+	repo.git(root, "init", "--quiet", "--initial-branch=main", repo.work)
+	repo.git(repo.work, "remote", "add", "origin", repo.bare)
+	return repo
+}
+
+func (r *templateRepo) git(dir string, args ...string) string {
+	r.t.Helper()
+	out, err := runner.NewExecRunner().Output(dir, "git", args...)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commit writes a pack whose README carries version and pushes it, along
+// with tag when it is not empty. It returns the new commit.
+func (r *templateRepo) commit(version, tag string) string {
+	r.t.Helper()
+	writeTree(r.t, r.work, map[string]string{
+		ManifestFile:         "name: kit\n",
+		"template/README.md": version + "\n",
+	})
+	r.git(r.work, "add", "-A")
+	r.git(r.work, "commit", "--quiet", "-m", version)
+	r.git(r.work, "push", "--quiet", "origin", "main")
+	if tag != "" {
+		r.git(r.work, "tag", tag)
+		r.git(r.work, "push", "--quiet", "origin", tag)
+	}
+	return r.git(r.work, "rev-parse", "HEAD")
+}
+
+func (r *templateRepo) source(ref string) *Source {
+	r.t.Helper()
+	raw := "git+file://" + r.bare
+	if ref != "" {
+		raw += "@" + ref
+	}
+	src, err := ParseSource(raw)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return src
+}
+
+func newTestCache(t *testing.T) (*Cache, *countingRunner) {
+	t.Helper()
+	t.Setenv("GPM_CACHE_DIR", t.TempDir())
+	r := &countingRunner{Runner: runner.NewExecRunner()}
+	cache, err := NewCache(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cache, r
+}
+
+func readPackFile(t *testing.T, pack *Pack, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(pack.Dir, pack.Manifest.Root, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(content))
+}
+
+func TestCacheFetchResolvesRefs(t *testing.T) {
+	repo := newTemplateRepo(t)
+	first := repo.commit("v1", "v1.0.0")
+	second := repo.commit("v2", "")
+	cache, _ := newTestCache(t)
+
+	tests := []struct {
+		ref        string
+		wantCommit string
+		wantReadme string
+	}{
+		{"v1.0.0", first, "v1"},
+		{"main", second, "v2"},
+		{"", second, "v2"},
+		{first, first, "v1"},
+		{first[:10], first, "v1"},
+	}
+	for _, tt := range tests {
+		t.Run("ref "+tt.ref, func(t *testing.T) {
+			pack, err := cache.Fetch(repo.source(tt.ref))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pack.Commit != tt.wantCommit {
+				t.Errorf("Commit = %s, want %s", pack.Commit, tt.wantCommit)
+			}
+			if got := readPackFile(t, pack, "README.md"); got != tt.wantReadme {
+				t.Errorf("README.md = %q, want %q", got, tt.wantReadme)
+			}
+			if _, err := os.Stat(filepath.Join(pack.Dir, ".git")); !os.IsNotExist(err) {
+				t.Error("checkout kept its .git directory")
+			}
+		})
+	}
+
+	if _, err := cache.Fetch(repo.source("v9.9.9")); err == nil || !strings.Contains(err.Error(), `ref "v9.9.9" not found`) {
+		t.Errorf("Fetch of a missing ref: error = %v", err)
+	}
+}
+
+func TestCacheReusesCheckoutPerCommit(t *testing.T) {
+	repo := newTemplateRepo(t)
+	commit := repo.commit("v1", "v1.0.0")
+	cache, r := newTestCache(t)
+
+	first, err := cache.Fetch(repo.source("v1.0.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.count("clone"); got != 2 {
+		t.Fatalf("first Fetch ran %d clones, want 2 (mirror and checkout)", got)
+	}
+
+	// The tag and the branch name the same commit, so both reuse the
+	// mirror and the checkout.
+	for _, ref := range []string{"v1.0.0", "main", commit} {
+		pack, err := cache.Fetch(repo.source(ref))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pack.Dir != first.Dir {
+			t.Errorf("Fetch(%s) checked out into %s, want the cached %s", ref, pack.Dir, first.Dir)
+		}
+	}
+	if got := r.count("clone"); got != 2 {
+		t.Errorf("cloned %d times in total, want no clone after the first Fetch", got)
+	}
+	if got := r.count("fetch"); got != 0 {
+		t.Errorf("fetched %d times, want 0 while the refs resolve locally", got)
+	}
+}
+
+func TestCacheUpdatePicksUpNewCommits(t *testing.T) {
+	repo := newTemplateRepo(t)
+	first := repo.commit("v1", "")
+	cache, _ := newTestCache(t)
+
+	old, err := cache.Fetch(repo.source("main"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := repo.commit("v2", "")
+
+	stale, err := cache.Fetch(repo.source("main"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stale.Commit != first {
+		t.Fatalf("Fetch before Update = %s, want the cached %s", stale.Commit, first)
+	}
+
+	updated, err := cache.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 1 || updated[0] != "file://"+repo.bare {
+		t.Errorf("Update() = %v, want [file://%s]", updated, repo.bare)
+	}
+
+	fresh, err := cache.Fetch(repo.source("main"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fresh.Commit != second {
+		t.Errorf("Fetch after Update = %s, want %s", fresh.Commit, second)
+	}
+	if got := readPackFile(t, fresh, "README.md"); got != "v2" {
+		t.Errorf("README.md = %q, want v2", got)
+	}
+	if got := readPackFile(t, old, "README.md"); got != "v1" {
+		t.Errorf("older checkout changed to %q, want it kept at v1", got)
+	}
+}
+
+func TestCacheUpdateWithEmptyCache(t *testing.T) {
+	cache, _ := newTestCache(t)
+	updated, err := cache.Update()
+	if err != nil || len(updated) != 0 {
+		t.Errorf("Update() = %v, %v; want nothing to update", updated, err)
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	localDir := t.TempDir()
+
+	tests := []struct {
+		raw     string
+		want    Source
+		wantErr string
+	}{
+		{
+			raw:  "git+file:///srv/kits/web@v1.2.0",
+			want: Source{URL: "file:///srv/kits/web", Ref: "v1.2.0"},
+		},
+		{
+			raw:  "git+file:///srv/kits/web",
+			want: Source{URL: "file:///srv/kits/web", Ref: "HEAD"},
+		},
+		{
+			raw:  "https://github.com/acme/kit.git@main",
+			want: Source{URL: "https://github.com/acme/kit.git", Ref: "main"},
+		},
+		{
+			raw:  "git+ssh://git@github.com/acme/kit.git@v2",
+			want: Source{URL: "ssh://git@github.com/acme/kit.git", Ref: "v2"},
+		},
+		{
+			raw:  "git+ssh://git@github.com/acme/kit.git",
+			want: Source{URL: "ssh://git@github.com/acme/kit.git", Ref: "HEAD"},
+		},
+		{
+			raw:  "  " + localDir + "  ",
+			want: Source{Path: localDir},
+		},
+		{raw: "", wantErr: "cannot be empty"},
+		{raw: "github.com/acme/kit", wantErr: "unsupported template source"},
+		{raw: "git+file:///srv/kits/web@", wantErr: "empty ref"},
+		{raw: "git+github.com/acme/kit", wantErr: "URL scheme"},
+		{raw: "git+-uupload-pack=touch /tmp/x://host/kit", wantErr: `cannot start its URL or ref with "-"`},
+		{raw: "git+file:///srv/kits/web@--orphan", wantErr: `cannot start its URL or ref with "-"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			src, err := ParseSource(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.want.Raw = strings.TrimSpace(tt.raw)
+			if *src != tt.want {
+				t.Errorf("ParseSource(%q) = %+v, want %+v", tt.raw, *src, tt.want)
+			}
+		})
+	}
+}
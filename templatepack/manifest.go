@@ -0,0 +1,228 @@
+package templatepack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const ManifestFile = "gpm-template.yaml"
+
+const (
+	QuestionString = "string"
+	QuestionBool   = "bool"
+	QuestionInt    = "int"
+	QuestionChoice = "choice"
+)
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type Manifest struct {
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description"`
+	Root        string     `yaml:"root"`
+	Questions   []Question `yaml:"questions"`
+	Variables   []Variable `yaml:"variables"`
+	Files       []FileRule `yaml:"files"`
+}
+
+// Question is asked by the wizard when its When condition holds. When,
+// Default and variable values are text/template snippets evaluated against
+// the answers collected so far, e.g. `when: eq .database "postgres"`.
+type Question struct {
+	Name     string   `yaml:"name"`
+	Prompt   string   `yaml:"prompt"`
+	Type     string   `yaml:"type"`
+	Default  any      `yaml:"default"`
+	Validate string   `yaml:"validate"`
+	Choices  []string `yaml:"choices"`
+	When     string   `yaml:"when"`
+
+	pattern *regexp.Regexp
+}
+
+type Variable struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// FileRule includes Path (a file or a whole directory under the root) only
+// when its When condition holds.
+type FileRule struct {
+	Path string `yaml:"path"`
+	When string `yaml:"when"`
+}
+
+func loadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template manifest: %w", err)
+	}
+
+	manifest := &Manifest{}
+	if err := yaml.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+	}
+	if err := manifest.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+	}
+	return manifest, nil
+}
+
+func (m *Manifest) validate() error {
+	if m.Root == "" {
+		m.Root = "template"
+	}
+	if !isInside(m.Root) {
+		return fmt.Errorf("root must stay inside the template")
+	}
+
+	seen := map[string]bool{"module_name": true, "project_name": true}
+	for i := range m.Questions {
+		q := &m.Questions[i]
+		if !identifierPattern.MatchString(q.Name) {
+			return fmt.Errorf("question %d: name %q must be a valid identifier", i+1, q.Name)
+		}
+		if seen[q.Name] {
+			return fmt.Errorf("question %q is declared more than once", q.Name)
+		}
+		seen[q.Name] = true
+
+		if q.Type == "" {
+			q.Type = QuestionString
+			if len(q.Choices) > 0 {
+				q.Type = QuestionChoice
+			}
+		}
+		switch q.Type {
+		case QuestionString, QuestionBool, QuestionInt:
+		case QuestionChoice:
+			if len(q.Choices) == 0 {
+				return fmt.Errorf("question %q: choice questions need choices", q.Name)
+			}
+		default:
+			return fmt.Errorf("question %q: unknown type %q", q.Name, q.Type)
+		}
+
+		if q.Validate != "" {
+			pattern, err := regexp.Compile(q.Validate)
+			if err != nil {
+				return fmt.Errorf("question %q: invalid validate pattern: %w", q.Name, err)
+			}
+			q.pattern = pattern
+		}
+		if q.Prompt == "" {
+			q.Prompt = q.Name
+		}
+	}
+
+	for _, v := range m.Variables {
+		if !identifierPattern.MatchString(v.Name) {
+			return fmt.Errorf("variable name %q must be a valid identifier", v.Name)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("variable %q clashes with a question or built-in", v.Name)
+		}
+		seen[v.Name] = true
+	}
+
+	for _, rule := range m.Files {
+		if rule.Path == "" || !isInside(rule.Path) {
+			return fmt.Errorf("file rule path %q must stay inside the template root", rule.Path)
+		}
+	}
+	return nil
+}
+
+// Enabled reports whether the question should be asked for the answers
+// collected so far.
+func (q *Question) Enabled(data map[string]any) (bool, error) {
+	return Condition(q.When, data)
+}
+
+// DefaultString renders the default as the text shown in a prompt.
+func (q *Question) DefaultString(data map[string]any) (string, error) {
+	if q.Default == nil {
+		return "", nil
+	}
+	return renderString(q.Name+".default", fmt.Sprint(q.Default), data)
+}
+
+// Parse checks raw input against the validate pattern, whatever the
+// question's type, and converts it to that type.
+func (q *Question) Parse(input string) (any, error) {
+	input = strings.TrimSpace(input)
+	if q.pattern != nil && !q.pattern.MatchString(input) {
+		return nil, fmt.Errorf("%s must match %s", q.Name, q.Validate)
+	}
+
+	switch q.Type {
+	case QuestionBool:
+		value, err := strconv.ParseBool(input)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", q.Name)
+		}
+		return value, nil
+	case QuestionInt:
+		value, err := strconv.Atoi(input)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a whole number", q.Name)
+		}
+		return value, nil
+	case QuestionChoice:
+		for _, choice := range q.Choices {
+			if choice == input {
+				return input, nil
+			}
+		}
+		return nil, fmt.Errorf("%s must be one of %s", q.Name, strings.Join(q.Choices, ", "))
+	}
+	return input, nil
+}
+
+// DefaultValue is what a skipped question resolves to, so templates can
+// still refer to it.
+func (q *Question) DefaultValue(data map[string]any) (any, error) {
+	text, err := q.DefaultString(data)
+	if err != nil {
+		return nil, err
+	}
+	if text == "" {
+		switch q.Type {
+		case QuestionBool:
+			return false, nil
+		case QuestionInt:
+			return 0, nil
+		case QuestionChoice:
+			return q.Choices[0], nil
+		}
+		return "", nil
+	}
+	return q.Parse(text)
+}
+
+// Condition evaluates a text/template pipeline such as `.use_db` or
+// `eq .database "postgres"`; an empty expression is always true.
+func Condition(expr string, data map[string]any) (bool, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return true, nil
+	}
+	expr = strings.TrimSuffix(strings.TrimPrefix(expr, "{{"), "}}")
+
+	out, err := renderString("when", "{{if "+expr+"}}true{{end}}", data)
+	if err != nil {
+		return false, err
+	}
+	return out == "true", nil
+}
+
+func isInside(path string) bool {
+	clean := filepath.Clean(path)
+	return !filepath.IsAbs(clean) && clean != ".." && !strings.HasPrefix(clean, ".."+string(filepath.Separator))
+}
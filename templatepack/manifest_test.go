@@ -0,0 +1,250 @@
+package templatepack
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func loadManifestFrom(t *testing.T, manifest string) (*Manifest, error) {
+	t.Helper()
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{ManifestFile: manifest})
+	return loadManifest(dir)
+}
+
+func TestLoadManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  string
+	}{
+		{
+			name: "valid",
+			manifest: `
+name: kit
+questions:
+  - name: service_name
+    validate: "^[a-z]+$"
+  - name: database
+    choices: [postgres, mysql]
+  - name: port
+    type: int
+variables:
+  - name: binary
+    value: "{{ .service_name }}-svc"
+files:
+  - path: internal/db
+    when: .use_db
+`,
+		},
+		{name: "invalid yaml", manifest: "questions: [", wantErr: "invalid gpm-template.yaml"},
+		{name: "root outside template", manifest: "root: ../elsewhere", wantErr: "root must stay inside"},
+		{name: "bad question name", manifest: "questions: [{name: service-name}]", wantErr: "valid identifier"},
+		{name: "duplicate question", manifest: "questions: [{name: a}, {name: a}]", wantErr: "more than once"},
+		{name: "question shadows built-in", manifest: "questions: [{name: module_name}]", wantErr: "more than once"},
+		{name: "unknown type", manifest: "questions: [{name: a, type: float}]", wantErr: `unknown type "float"`},
+		{name: "choice without choices", manifest: "questions: [{name: a, type: choice}]", wantErr: "need choices"},
+		{name: "bad pattern", manifest: `questions: [{name: a, validate: "("}]`, wantErr: "invalid validate pattern"},
+		{name: "variable clash", manifest: "questions: [{name: a}]\nvariables: [{name: a, value: x}]", wantErr: "clashes"},
+		{name: "file rule escapes root", manifest: "files: [{path: ../secret}]", wantErr: "must stay inside"},
+		{name: "empty file rule", manifest: "files: [{when: .x}]", wantErr: "must stay inside"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := loadManifestFrom(t, tt.manifest)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m.Root != "template" {
+				t.Errorf("Root = %q, want the default template", m.Root)
+			}
+			if got := m.Questions[0].Type; got != QuestionString {
+				t.Errorf("untyped question has type %q, want string", got)
+			}
+			if got := m.Questions[0].Prompt; got != "service_name" {
+				t.Errorf("Prompt = %q, want the name as a fallback", got)
+			}
+			if got := m.Questions[1].Type; got != QuestionChoice {
+				t.Errorf("question with choices has type %q, want choice", got)
+			}
+		})
+	}
+}
+
+func TestQuestionParse(t *testing.T) {
+	m, err := loadManifestFrom(t, `
+questions:
+  - name: service
+    validate: "^[a-z][a-z0-9-]*$"
+  - name: port
+    type: int
+    validate: "^[1-9][0-9]{3,4}$"
+  - name: use_db
+    type: bool
+  - name: database
+    choices: [postgres, mysql]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	questions := make(map[string]*Question)
+	for i := range m.Questions {
+		questions[m.Questions[i].Name] = &m.Questions[i]
+	}
+
+	tests := []struct {
+		question string
+		input    string
+		want     any
+		wantErr  string
+	}{
+		{"service", " billing-api ", "billing-api", ""},
+		{"service", "Billing", nil, "must match"},
+		{"port", "8080", 8080, ""},
+		{"port", "80", nil, "must match"},
+		{"port", "80a0", nil, "must match"},
+		{"use_db", "true", true, ""},
+		{"use_db", "yes", nil, "true or false"},
+		{"database", "mysql", "mysql", ""},
+		{"database", "sqlite", nil, "must be one of postgres, mysql"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.question+"/"+tt.input, func(t *testing.T) {
+			got, err := questions[tt.question].Parse(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuestionDefaultValue(t *testing.T) {
+	m, err := loadManifestFrom(t, `
+questions:
+  - name: service
+    default: "{{ .project_name }}-svc"
+  - name: replicas
+    type: int
+    default: 3
+  - name: port
+    type: int
+    validate: "^[0-9]{4}$"
+    default: 80
+  - name: use_db
+    type: bool
+  - name: count
+    type: int
+  - name: database
+    choices: [postgres, mysql]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := BaseData("example.com/acme/billing", "billing")
+
+	tests := []struct {
+		index   int
+		want    any
+		wantErr bool
+	}{
+		{0, "billing-svc", false},
+		{1, 3, false},
+		{2, nil, true},
+		{3, false, false},
+		{4, 0, false},
+		{5, "postgres", false},
+	}
+	for _, tt := range tests {
+		q := &m.Questions[tt.index]
+		t.Run(q.Name, func(t *testing.T) {
+			got, err := q.DefaultValue(data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("DefaultValue() = %#v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("DefaultValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCondition(t *testing.T) {
+	data := map[string]any{
+		"use_db":   true,
+		"database": "postgres",
+		"replicas": 0,
+	}
+
+	tests := []struct {
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{"", true, false},
+		{"  ", true, false},
+		{".use_db", true, false},
+		{"not .use_db", false, false},
+		{`eq .database "postgres"`, true, false},
+		{`{{ eq .database "mysql" }}`, false, false},
+		{`and .use_db (eq .database "postgres")`, true, false},
+		{".replicas", false, false},
+		{".missing", false, true},
+		{"eq .database", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Condition(tt.expr, data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Condition(%q) = %v, want an error", tt.expr, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Condition(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
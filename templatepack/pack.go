@@ -0,0 +1,169 @@
+package templatepack
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+type Pack struct {
+	Source   string
+	Commit   string
+	Dir      string
+	Manifest *Manifest
+}
+
+func Load(dir string) (*Pack, error) {
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Pack{Dir: dir, Manifest: manifest}, nil
+}
+
+func (p *Pack) Name() string {
+	if p.Manifest.Name != "" {
+		return p.Manifest.Name
+	}
+	return filepath.Base(p.Dir)
+}
+
+// BaseData holds the values every pack can use before any question is
+// answered.
+func BaseData(moduleName, projectName string) map[string]any {
+	return map[string]any{
+		"module_name":  moduleName,
+		"project_name": projectName,
+	}
+}
+
+// Render copies the pack's root into dest. Path segments may contain
+// template actions, and files ending in .tmpl are executed with data and
+// written without the suffix; everything else is copied verbatim. Declared
+// variables are added to data first, and paths whose file rule evaluates
+// to false are skipped. Symlinks and paths that render outside dest are
+// rejected.
+func (p *Pack) Render(dest string, data map[string]any) error {
+	data, err := p.resolveVariables(data)
+	if err != nil {
+		return err
+	}
+	excluded, err := p.excludedPaths(data)
+	if err != nil {
+		return err
+	}
+
+	root := filepath.Join(p.Dir, p.Manifest.Root)
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if d.Name() == ".git" || rel == ManifestFile || excluded[rel] || excluded[strings.TrimSuffix(rel, ".tmpl")] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// A link could pull any file on the host into the project.
+		if d.Type()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%s is a symlink, which template packs cannot contain", rel)
+		}
+
+		name, err := renderString(rel, rel, data)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, name)
+		if inside, err := filepath.Rel(dest, target); err != nil || inside == "." || filepath.IsAbs(inside) ||
+			inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("%s renders to %q, which is not inside the project", rel, name)
+		}
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		return renderFile(path, target, rel, data)
+	})
+}
+
+func (p *Pack) resolveVariables(data map[string]any) (map[string]any, error) {
+	resolved := maps.Clone(data)
+	for _, v := range p.Manifest.Variables {
+		value, err := renderString(v.Name, v.Value, resolved)
+		if err != nil {
+			return nil, fmt.Errorf("variable %q: %w", v.Name, err)
+		}
+		resolved[v.Name] = value
+	}
+	return resolved, nil
+}
+
+func (p *Pack) excludedPaths(data map[string]any) (map[string]bool, error) {
+	excluded := make(map[string]bool)
+	for _, rule := range p.Manifest.Files {
+		include, err := Condition(rule.When, data)
+		if err != nil {
+			return nil, fmt.Errorf("file rule %q: %w", rule.Path, err)
+		}
+		if !include {
+			excluded[filepath.Clean(rule.Path)] = true
+		}
+	}
+	return excluded, nil
+}
+
+func renderFile(src, target, name string, data map[string]any) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", name)
+	}
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(target, ".tmpl") {
+		rendered, err := renderString(name, string(content), data)
+		if err != nil {
+			return err
+		}
+		content = []byte(rendered)
+		target = strings.TrimSuffix(target, ".tmpl")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(target, content, info.Mode().Perm())
+}
+
+func renderString(name, text string, data map[string]any) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,177 @@
+package templatepack
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testManifest = `
+name: kit
+questions:
+  - name: use_db
+    type: bool
+  - name: database
+    choices: [postgres, mysql]
+    when: .use_db
+variables:
+  - name: binary
+    value: "{{ .project_name }}-svc"
+files:
+  - path: internal/db
+    when: .use_db
+  - path: deploy/mysql.yaml
+    when: eq .database "mysql"
+  - path: ./docs/
+    when: "false"
+`
+
+func TestExcludedPaths(t *testing.T) {
+	manifest, err := loadManifestFrom(t, testManifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pack := &Pack{Manifest: manifest}
+
+	tests := []struct {
+		name string
+		data map[string]any
+		want map[string]bool
+	}{
+		{
+			name: "postgres",
+			data: map[string]any{"use_db": true, "database": "postgres"},
+			want: map[string]bool{"deploy/mysql.yaml": true, "docs": true},
+		},
+		{
+			name: "mysql",
+			data: map[string]any{"use_db": true, "database": "mysql"},
+			want: map[string]bool{"docs": true},
+		},
+		{
+			name: "no database",
+			data: map[string]any{"use_db": false, "database": "postgres"},
+			want: map[string]bool{"internal/db": true, "deploy/mysql.yaml": true, "docs": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pack.excludedPaths(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("excludedPaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := pack.excludedPaths(map[string]any{}); err == nil {
+		t.Error("excludedPaths() with a missing answer succeeded, want an error")
+	}
+}
+
+func TestRender(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		ManifestFile:                            testManifest,
+		"template/README.md.tmpl":               "# {{ .project_name }} ({{ .binary }})\n",
+		"template/cmd/{{.binary}}/main.go.tmpl": "package main // {{ .module_name }}\n",
+		"template/internal/db/db.go":            "package db // {{ left alone }}\n",
+		"template/deploy/mysql.yaml.tmpl":       "image: mysql\n",
+		"template/docs/index.md":                "docs\n",
+	})
+	pack, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest := t.TempDir()
+	data := BaseData("example.com/acme/billing", "billing")
+	data["use_db"] = true
+	data["database"] = "postgres"
+	if err := pack.Render(dest, data); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"README.md":               "# billing (billing-svc)\n",
+		"cmd/billing-svc/main.go": "package main // example.com/acme/billing\n",
+		"internal/db/db.go":       "package db // {{ left alone }}\n",
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(filepath.Join(dest, name))
+		if err != nil {
+			t.Errorf("missing %s: %v", name, err)
+			continue
+		}
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", name, got, content)
+		}
+	}
+	for _, name := range []string{"deploy/mysql.yaml", "docs", ManifestFile} {
+		if _, err := os.Stat(filepath.Join(dest, name)); !os.IsNotExist(err) {
+			t.Errorf("%s was rendered, want it excluded", name)
+		}
+	}
+}
+
+func TestRenderRejectsPathsOutsideDest(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data map[string]any
+	}{
+		{"answer with ../", "template/{{ .service }}.go.tmpl", map[string]any{"service": "../../escaped"}},
+		{"answer of ..", "template/{{ .service }}/main.go", map[string]any{"service": ".."}},
+		{"nested ../", "template/cmd/{{ .service }}", map[string]any{"service": "../../../escaped"}},
+		{"empty name", "template/{{ .service }}", map[string]any{"service": ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, map[string]string{ManifestFile: "name: kit\n", tt.file: "package main\n"})
+			pack, err := Load(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parent := t.TempDir()
+			dest := filepath.Join(parent, "a", "project")
+			err = pack.Render(dest, tt.data)
+			if err == nil || !strings.Contains(err.Error(), "not inside the project") {
+				t.Fatalf("error = %v, want the path rejected", err)
+			}
+			if _, err := os.Stat(filepath.Join(parent, "escaped.go")); !os.IsNotExist(err) {
+				t.Error("a file was written outside dest")
+			}
+		})
+	}
+}
+
+func TestRenderRejectsSymlinks(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secret, []byte("token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{ManifestFile: "name: kit\n", "template/README.md": "kit\n"})
+	if err := os.Symlink(secret, filepath.Join(dir, "template", "config.txt")); err != nil {
+		t.Skip("symlinks are not supported here:", err)
+	}
+	pack, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest := t.TempDir()
+	if err := pack.Render(dest, map[string]any{}); err == nil || !strings.Contains(err.Error(), "symlink") {
+		t.Fatalf("error = %v, want the symlink rejected", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "config.txt")); !os.IsNotExist(err) {
+		t.Error("the symlink target was copied into the project")
+	}
+}
@@ -0,0 +1,57 @@
+package templatepack
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+type Source struct {
+	Raw  string
+	URL  string
+	Ref  string
+	Path string
+}
+
+// ParseSource accepts git+<url>[@ref] as well as bare https URLs, e.g.
+// git+file:///srv/kits/web@v1.2.0 or https://github.com/acme/kit.git@main.
+// A path to a local directory is used in place, which is handy while a
+// pack is being written.
+func ParseSource(raw string) (*Source, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("template source cannot be empty")
+	}
+	if info, err := os.Stat(raw); err == nil && info.IsDir() {
+		return &Source{Raw: raw, Path: raw}, nil
+	}
+
+	location := strings.TrimPrefix(raw, "git+")
+	if location == raw && !strings.HasPrefix(raw, "https://") && !strings.HasPrefix(raw, "http://") {
+		return nil, fmt.Errorf("unsupported template source %q (expected git+<url>[@ref], https://... or a local directory)", raw)
+	}
+
+	src := &Source{Raw: raw, URL: location, Ref: "HEAD"}
+	// The ref separator is the last "@" after the last "/", so user@host
+	// style URLs are left alone.
+	if at := strings.LastIndex(location, "@"); at > strings.LastIndex(location, "/") {
+		src.URL = location[:at]
+		src.Ref = location[at+1:]
+		if src.Ref == "" {
+			return nil, fmt.Errorf("template source %q has an empty ref", raw)
+		}
+	}
+
+	if !strings.Contains(src.URL, "://") {
+		return nil, fmt.Errorf("template source %q must include a URL scheme", raw)
+	}
+	// git would read a leading dash as an option.
+	if strings.HasPrefix(src.URL, "-") || strings.HasPrefix(src.Ref, "-") {
+		return nil, fmt.Errorf("template source %q cannot start its URL or ref with \"-\"", raw)
+	}
+	return src, nil
+}
+
+func (s *Source) String() string {
+	return s.Raw
+}
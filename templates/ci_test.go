@@ -0,0 +1,54 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+)
+
+func TestCIReleaseStampsDeclaredVars(t *testing.T) {
+	for _, provider := range CIProviders {
+		if provider == "none" {
+			continue
+		}
+		for _, cfg := range appTypeConfigs() {
+			cfg.CI = provider
+			t.Run(provider+"/"+cfg.AppType, func(t *testing.T) {
+				files, err := (&TemplateGenerator{}).GetCITemplates(cfg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var release []string
+				for _, content := range files {
+					for _, line := range strings.Split(content, "\n") {
+						if strings.Contains(line, "-X main.") {
+							release = append(release, line)
+						}
+					}
+				}
+
+				// Only CLIs publish release binaries.
+				if !cfg.IsCLI() {
+					if len(release) > 0 {
+						t.Errorf("%s pipeline builds release binaries: %v", cfg.AppType, release)
+					}
+					return
+				}
+				if len(release) == 0 {
+					t.Fatal("no release build stamps the version")
+				}
+				for _, line := range release {
+					expectStampedVars(t, cfg, line)
+				}
+			})
+		}
+	}
+}
+
+func TestCIUnknownProvider(t *testing.T) {
+	cfg := &config.Config{ModuleName: "example.com/acme/tool", AppType: "cli", CI: "jenkins"}
+	if _, err := (&TemplateGenerator{}).GetCITemplates(cfg); err == nil || !strings.Contains(err.Error(), `unsupported CI provider "jenkins"`) {
+		t.Errorf("error = %v, want an unsupported provider error", err)
+	}
+}
@@ -0,0 +1,128 @@
+package templates
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+)
+
+// deployData adds the settings of .env.example to the project
+// configuration, split into plain configuration and secrets.
+type deployData struct {
+	*config.Config
+	Env     []envEntry
+	Secrets []envEntry
+}
+
+// envEntry is one KEY=value line, with Value quoted for YAML.
+type envEntry struct {
+	Key   string
+	Value string
+}
+
+// helmTemplates are copied into the chart as they are; Helm renders them.
+var helmTemplates = map[string]string{
+	"templates/_helpers.tpl":    "deploy/helm/templates/helpers.tpl",
+	"templates/deployment.yaml": "deploy/helm/templates/deployment.yaml",
+	"templates/service.yaml":    "deploy/helm/templates/service.yaml",
+	"templates/configmap.yaml":  "deploy/helm/templates/configmap.yaml",
+	"templates/secret.yaml":     "deploy/helm/templates/secret.yaml",
+	".helmignore":               "deploy/helm/helmignore",
+}
+
+// GetDeployTemplates renders deploy/ for cfg.Deploy: plain manifests with
+// a kustomization under deploy/k8s, or a chart under deploy/helm/<name>.
+// Both take their environment from .env.example and probe /health.
+func (tg *TemplateGenerator) GetDeployTemplates(cfg *config.Config) (map[string]string, error) {
+	if !cfg.HasHealthEndpoint() {
+		return nil, fmt.Errorf("deploy manifests need an app that serves /health, not %q", cfg.AppType)
+	}
+	data, err := tg.newDeployData(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Deploy {
+	case "kubernetes":
+		names := map[string]string{
+			"deploy/k8s/kustomization.yaml": "deploy/k8s/kustomization.yaml.tmpl",
+			"deploy/k8s/deployment.yaml":    "deploy/k8s/deployment.yaml.tmpl",
+			"deploy/k8s/service.yaml":       "deploy/k8s/service.yaml.tmpl",
+			"deploy/k8s/configmap.yaml":     "deploy/k8s/configmap.yaml.tmpl",
+		}
+		if len(data.Secrets) > 0 {
+			names["deploy/k8s/secret.yaml"] = "deploy/k8s/secret.yaml.tmpl"
+		}
+		return renderFiles(names, data)
+	case "helm":
+		dir := "deploy/helm/" + cfg.DNSName() + "/"
+		rendered, err := renderFiles(map[string]string{
+			dir + "Chart.yaml":  "deploy/helm/Chart.yaml.tmpl",
+			dir + "values.yaml": "deploy/helm/values.yaml.tmpl",
+		}, data)
+		if err != nil {
+			return nil, err
+		}
+		for target, name := range helmTemplates {
+			content, err := readFile(name)
+			if err != nil {
+				return nil, err
+			}
+			rendered[dir+target] = content
+		}
+		return rendered, nil
+	}
+	return nil, fmt.Errorf("unsupported deploy target %q", cfg.Deploy)
+}
+
+func (tg *TemplateGenerator) newDeployData(cfg *config.Config) (deployData, error) {
+	env, err := tg.GetEnvExampleTemplate(cfg)
+	if err != nil {
+		return deployData{}, err
+	}
+
+	data := deployData{Config: cfg}
+	scanner := bufio.NewScanner(strings.NewReader(env))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.HasPrefix(line, "#") {
+			continue
+		}
+		switch {
+		case key == "APP_ENV":
+			value = "production"
+		case key == "DATABASE_URL" && cfg.Database() == "sqlite":
+			// The same path the Docker image uses, on the /data volume.
+			value = "/data/" + cfg.ProjectName() + ".db"
+		}
+
+		entry := envEntry{Key: key, Value: strconv.Quote(value)}
+		if isSecretEnv(cfg, key) {
+			data.Secrets = append(data.Secrets, entry)
+		} else {
+			data.Env = append(data.Env, entry)
+		}
+	}
+	return data, nil
+}
+
+// isSecretEnv reports whether key holds credentials: a connection string
+// for a database server, or anything named like a password or token.
+func isSecretEnv(cfg *config.Config, key string) bool {
+	switch key {
+	case "DATABASE_URL":
+		return cfg.Database() != "sqlite"
+	case "MONGO_URI":
+		return true
+	}
+	for _, word := range []string{"PASSWORD", "SECRET", "TOKEN"} {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+// deployEnv renders cfg's deploy target and returns the plain settings and
+// the secrets it produces, keyed by environment variable.
+func deployEnv(t *testing.T, cfg *config.Config) (plain, secret map[string]string) {
+	t.Helper()
+	files, err := (&TemplateGenerator{}).GetDeployTemplates(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decode := func(name string, v any) {
+		t.Helper()
+		if err := yaml.Unmarshal([]byte(files[name]), v); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+
+	switch cfg.Deploy {
+	case "kubernetes":
+		var configMap struct{ Data map[string]string }
+		var secrets struct {
+			StringData map[string]string `yaml:"stringData"`
+		}
+		decode("deploy/k8s/configmap.yaml", &configMap)
+		if _, ok := files["deploy/k8s/secret.yaml"]; ok {
+			decode("deploy/k8s/secret.yaml", &secrets)
+			if !strings.Contains(files["deploy/k8s/kustomization.yaml"], "- secret.yaml") {
+				t.Error("kustomization.yaml does not list secret.yaml")
+			}
+		}
+		return configMap.Data, secrets.StringData
+	case "helm":
+		var values struct {
+			Config  map[string]string
+			Secrets map[string]string
+		}
+		decode("deploy/helm/"+cfg.DNSName()+"/values.yaml", &values)
+		return values.Config, values.Secrets
+	}
+	t.Fatalf("unknown deploy target %q", cfg.Deploy)
+	return nil, nil
+}
+
+func TestDeploySecrets(t *testing.T) {
+	tests := []struct {
+		name    string
+		appType string
+		deps    []string
+		secret  []string
+		plain   []string
+	}{
+		{
+			name:    "postgres",
+			appType: "web",
+			deps:    []string{"gorm.io/driver/postgres"},
+			secret:  []string{"DATABASE_URL"},
+			plain:   []string{"APP_ENV", "PORT"},
+		},
+		{
+			name:    "mysql worker",
+			appType: "worker",
+			deps:    []string{"gorm.io/driver/mysql"},
+			secret:  []string{"DATABASE_URL"},
+			plain:   []string{"WORKER_CONCURRENCY"},
+		},
+		{
+			name:    "mongo and redis",
+			appType: "web",
+			deps:    []string{"go.mongodb.org/mongo-driver/mongo", "github.com/redis/go-redis/v9"},
+			secret:  []string{"MONGO_URI", "REDIS_PASSWORD"},
+			plain:   []string{"MONGO_DATABASE", "REDIS_ADDR"},
+		},
+		{
+			// A file path on the pod's volume is not a credential.
+			name:    "sqlite",
+			appType: "web",
+			deps:    []string{"gorm.io/driver/sqlite"},
+			plain:   []string{"DATABASE_URL"},
+		},
+	}
+	for _, tt := range tests {
+		for _, target := range []string{"kubernetes", "helm"} {
+			t.Run(tt.name+"/"+target, func(t *testing.T) {
+				cfg := &config.Config{
+					ModuleName:           "example.com/acme/orders",
+					AppType:              tt.appType,
+					Framework:            "gin",
+					Deploy:               target,
+					SelectedDependencies: tt.deps,
+				}
+				plain, secret := deployEnv(t, cfg)
+				for _, key := range tt.secret {
+					if _, ok := secret[key]; !ok {
+						t.Errorf("%s is not in the Secret: %v", key, secret)
+					}
+					if _, ok := plain[key]; ok {
+						t.Errorf("%s is in the ConfigMap", key)
+					}
+				}
+				for _, key := range tt.plain {
+					if _, ok := plain[key]; !ok {
+						t.Errorf("%s is not in the ConfigMap: %v", key, plain)
+					}
+					if _, ok := secret[key]; ok {
+						t.Errorf("%s is in the Secret", key)
+					}
+				}
+				if plain["APP_ENV"] != "production" {
+					t.Errorf("APP_ENV = %q, want production", plain["APP_ENV"])
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package templates
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+)
+
+// editorPatterns is a line each editor's section ignores.
+var editorPatterns = map[string]string{
+	"vscode": ".vscode/*",
+	"goland": ".idea/",
+	"vim":    "*.swp",
+}
+
+func TestGitignore(t *testing.T) {
+	for _, appType := range []string{"web", "library"} {
+		for _, vendor := range []bool{false, true} {
+			for _, sqlite := range []bool{false, true} {
+				for _, editor := range Editors {
+					cfg := &config.Config{
+						ModuleName: "example.com/acme/kit",
+						AppType:    appType,
+						Framework:  "gin",
+						UseVendor:  vendor,
+						Editor:     editor,
+					}
+					if sqlite {
+						cfg.SelectedDependencies = []string{"gorm.io/driver/sqlite"}
+					}
+					name := fmt.Sprintf("%s/vendor=%t/sqlite=%t/%s", appType, vendor, sqlite, editor)
+					t.Run(name, func(t *testing.T) {
+						content, err := (&TemplateGenerator{}).GetGitignoreTemplate(cfg)
+						if err != nil {
+							t.Fatal(err)
+						}
+						lines := strings.Split(content, "\n")
+						ignores := func(pattern string) bool { return slices.Contains(lines, pattern) }
+
+						// go.sum pins dependency checksums and must be committed.
+						if ignores("go.sum") {
+							t.Error("go.sum is ignored")
+						}
+						if ignores("/vendor/") == vendor {
+							t.Errorf("/vendor/ ignored = %t with UseVendor = %t", !vendor, vendor)
+						}
+						if ignores("*.db") != sqlite {
+							t.Errorf("*.db ignored = %t with sqlite = %t", !sqlite, sqlite)
+						}
+						if ignores("/"+BinDir+"/") == cfg.IsLibrary() {
+							t.Errorf("/%s/ ignored = %t for a %s", BinDir, cfg.IsLibrary(), appType)
+						}
+						if ignores(".env") == cfg.IsLibrary() {
+							t.Errorf(".env ignored = %t for a %s", cfg.IsLibrary(), appType)
+						}
+						for other, pattern := range editorPatterns {
+							if ignores(pattern) != (other == editor) {
+								t.Errorf("%s ignored = %t with editor %s", pattern, other != editor, editor)
+							}
+						}
+					})
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package templates
+
+import (
+	"fmt"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+)
+
+// Licenses are the SPDX identifiers the wizard offers, plus proprietary
+// and none.
+var Licenses = []string{"MIT", "Apache-2.0", "BSD-3-Clause", "GPL-3.0-or-later", "MPL-2.0", "proprietary", "none"}
+
+// licenses maps each choice to its full text under files/license and the
+// name the README uses for it.
+var licenses = map[string]struct {
+	file string
+	name string
+}{
+	"MIT":              {"mit.txt.tmpl", "MIT License"},
+	"Apache-2.0":       {"apache-2.0.txt.tmpl", "Apache License 2.0"},
+	"BSD-3-Clause":     {"bsd-3-clause.txt.tmpl", "BSD 3-Clause License"},
+	"GPL-3.0-or-later": {"gpl-3.0.txt.tmpl", "GNU General Public License v3.0 or later"},
+	"MPL-2.0":          {"mpl-2.0.txt.tmpl", "Mozilla Public License 2.0"},
+	"proprietary":      {"proprietary.txt.tmpl", "proprietary license"},
+}
+
+// GetLicenseTemplate renders the full text of cfg.License with the
+// copyright holder and year filled in where the license has a place for
+// them.
+func (tg *TemplateGenerator) GetLicenseTemplate(cfg *config.Config) (string, error) {
+	license, ok := licenses[cfg.License]
+	if !ok {
+		return "", fmt.Errorf("unsupported license %q", cfg.License)
+	}
+	return render("license/"+license.file, cfg)
+}
+
+// GetLicenseReadmeSection is the License section of the generated README.
+func (tg *TemplateGenerator) GetLicenseReadmeSection(cfg *config.Config) string {
+	if cfg.License == "proprietary" {
+		return fmt.Sprintf("## License\n\nCopyright (c) %s %s. All rights reserved. See [LICENSE](LICENSE).\n",
+			cfg.LicenseYear, cfg.LicenseAuthor)
+	}
+	return fmt.Sprintf("## License\n\nCopyright (c) %s %s. Released under the %s (`%s`); see [LICENSE](LICENSE).\n",
+		cfg.LicenseYear, cfg.LicenseAuthor, licenses[cfg.License].name, cfg.SPDXIdentifier())
+}
+
+// GetSPDXHeader returns the comment lines that open every generated Go
+// file when SPDX headers are enabled.
+func (tg *TemplateGenerator) GetSPDXHeader(cfg *config.Config) string {
+	return fmt.Sprintf("// SPDX-FileCopyrightText: %s %s\n// SPDX-License-Identifier: %s\n",
+		cfg.LicenseYear, cfg.LicenseAuthor, cfg.SPDXIdentifier())
+}
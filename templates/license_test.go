@@ -0,0 +1,71 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+)
+
+func TestLicenses(t *testing.T) {
+	tests := []struct {
+		license string
+		spdx    string
+		text    string
+		readme  string
+	}{
+		{"MIT", "MIT", "Copyright (c) 2026 Ada Lovelace", "Released under the MIT License (`MIT`)"},
+		{"Apache-2.0", "Apache-2.0", "Copyright 2026 Ada Lovelace", "Released under the Apache License 2.0 (`Apache-2.0`)"},
+		{"BSD-3-Clause", "BSD-3-Clause", "Copyright (c) 2026, Ada Lovelace", "Released under the BSD 3-Clause License (`BSD-3-Clause`)"},
+		{"GPL-3.0-or-later", "GPL-3.0-or-later", "GNU GENERAL PUBLIC LICENSE", "Released under the GNU General Public License v3.0 or later (`GPL-3.0-or-later`)"},
+		{"MPL-2.0", "MPL-2.0", "Mozilla Public License Version 2.0", "Released under the Mozilla Public License 2.0 (`MPL-2.0`)"},
+		{"proprietary", "LicenseRef-Proprietary", "Copyright (c) 2026 Ada Lovelace. All rights reserved.", "Copyright (c) 2026 Ada Lovelace. All rights reserved."},
+	}
+	tg := &TemplateGenerator{}
+	for _, tt := range tests {
+		t.Run(tt.license, func(t *testing.T) {
+			cfg := &config.Config{ModuleName: "example.com/acme/kit", License: tt.license, LicenseAuthor: "Ada Lovelace", LicenseYear: "2026"}
+			if got := cfg.SPDXIdentifier(); got != tt.spdx {
+				t.Errorf("SPDXIdentifier() = %q, want %q", got, tt.spdx)
+			}
+
+			text, err := tg.GetLicenseTemplate(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(text, tt.text) {
+				t.Errorf("LICENSE does not contain %q", tt.text)
+			}
+			if strings.Contains(text, "{{") || strings.Contains(text, "<no value>") {
+				t.Error("LICENSE has an unfilled placeholder")
+			}
+
+			readme := tg.GetLicenseReadmeSection(cfg)
+			if !strings.Contains(readme, tt.readme) || !strings.Contains(readme, "[LICENSE](LICENSE)") {
+				t.Errorf("README section = %q, want it to contain %q and link LICENSE", readme, tt.readme)
+			}
+
+			want := "// SPDX-FileCopyrightText: 2026 Ada Lovelace\n// SPDX-License-Identifier: " + tt.spdx + "\n"
+			if got := tg.GetSPDXHeader(cfg); got != want {
+				t.Errorf("GetSPDXHeader() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestLicensesCoverTheChoices(t *testing.T) {
+	for _, license := range Licenses {
+		if _, ok := licenses[license]; !ok && license != "none" {
+			t.Errorf("%s is offered but has no license text", license)
+		}
+	}
+}
+
+func TestUnsupportedLicense(t *testing.T) {
+	for _, license := range []string{"none", "WTFPL"} {
+		cfg := &config.Config{License: license}
+		if _, err := (&TemplateGenerator{}).GetLicenseTemplate(cfg); err == nil || !strings.Contains(err.Error(), "unsupported license") {
+			t.Errorf("%s: error = %v, want an unsupported license error", license, err)
+		}
+	}
+}
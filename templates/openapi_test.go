@@ -0,0 +1,187 @@
+package templates
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+	"github.com/SwanHtetAungPhyo/gostart/openapi"
+)
+
+const petsSpec = `
+openapi: 3.1.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, maximum: 100}}
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/Pet"}
+      responses:
+        "201":
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+  /pets/{petId}:
+    delete:
+      operationId: deletePet
+      parameters:
+        - {name: petId, in: path, schema: {type: integer, format: int64}}
+      responses: {"204": {description: deleted}}
+  /pets/{petId}/name:
+    get:
+      operationId: petName
+      responses:
+        "200":
+          content:
+            text/plain:
+              schema: {type: string}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, pattern: "^[a-z]+$"}
+        species: {type: string, enum: [dog, cat]}
+        tag: {type: [string, "null"]}
+`
+
+func renderSpec(t *testing.T, framework string) map[string]string {
+	t.Helper()
+	doc, err := openapi.Parse([]byte(petsSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api, err := openapi.Build(doc, "api/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{ModuleName: "example.com/acme/pets", AppType: "web", Framework: framework}
+	generated, stubs, err := (&TemplateGenerator{}).GetOpenAPITemplates(cfg, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range stubs {
+		generated[name] = content
+	}
+	return generated
+}
+
+func TestOpenAPIRenderedCodeParses(t *testing.T) {
+	for _, framework := range WebFrameworks {
+		t.Run(framework, func(t *testing.T) {
+			files := renderSpec(t, framework)
+			for _, name := range []string{
+				"internal/model/openapi.gen.go",
+				"internal/handler/openapi.gen.go",
+				"internal/handler/openapi_routes.gen.go",
+				"internal/handler/api.go",
+			} {
+				content, ok := files[name]
+				if !ok {
+					t.Errorf("%s was not rendered", name)
+					continue
+				}
+				if _, err := format.Source([]byte(content)); err != nil {
+					t.Errorf("%s does not parse: %v\n%s", name, err, content)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAPIRenderedCode(t *testing.T) {
+	files := renderSpec(t, "stdlib")
+
+	tests := []struct {
+		file string
+		want []string
+		not  []string
+	}{
+		{
+			file: "internal/model/openapi.gen.go",
+			want: []string{
+				"// Code generated by gpm from api/openapi.yaml; DO NOT EDIT.",
+				"type Pet struct {",
+				"Name string `json:\"name\"`",
+				"Species *PetSpecies `json:\"species,omitempty\"`",
+				"Tag *string `json:\"tag,omitempty\"`",
+				"type PetSpecies string",
+				`PetSpeciesDog PetSpecies = "dog"`,
+				`pattern1 = regexp.MustCompile("^[a-z]+$")`,
+				`return requireFields(data, "name")`,
+				"type ListPetsParams struct {",
+				"Limit *int",
+				"type DeletePetParams struct {",
+				"PetID int64",
+			},
+			not: []string{"PetNameParams"},
+		},
+		{
+			file: "internal/handler/openapi.gen.go",
+			want: []string{
+				"ListPets(ctx context.Context, params model.ListPetsParams) ([]model.Pet, error)",
+				"CreatePet(ctx context.Context, params model.CreatePetParams) (*model.Pet, error)",
+				"DeletePet(ctx context.Context, params model.DeletePetParams) error",
+				"return apiResult{status: 201, body: result}",
+				"return apiResult{status: 204}",
+				`return apiBadRequest("request body is required")`,
+			},
+			// Text responses are skipped rather than generated without
+			// their body.
+			not: []string{"PetName"},
+		},
+		{
+			file: "internal/handler/openapi_routes.gen.go",
+			want: []string{"/pets/{petId}"},
+			not:  []string{"/name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			// gofmt aligns struct fields, so compare with spaces collapsed.
+			content := squash(files[tt.file])
+			for _, want := range tt.want {
+				if !strings.Contains(content, squash(want)) {
+					t.Errorf("missing %q in:\n%s", want, content)
+				}
+			}
+			for _, not := range tt.not {
+				if strings.Contains(content, not) {
+					t.Errorf("unexpected %q in:\n%s", not, content)
+				}
+			}
+		})
+	}
+}
+
+func squash(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func TestOpenAPIRenderedCodeIsDeterministic(t *testing.T) {
+	for _, framework := range WebFrameworks {
+		first := renderSpec(t, framework)
+		for range 10 {
+			for name, content := range renderSpec(t, framework) {
+				if content != first[name] {
+					t.Fatalf("%s: %s differs between runs", framework, name)
+				}
+			}
+		}
+	}
+}
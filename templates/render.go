@@ -0,0 +1,61 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"path"
+	"strings"
+	"text/template"
+)
+
+//go:embed files
+var files embed.FS
+
+// render executes files/<name>. Generated Go sources are passed through
+// gofmt so templates can use conditional blocks without worrying about
+// blank lines or import alignment. Blocks defined under files/partials are
+// available to every template.
+func render(name string, data any) (string, error) {
+	tmpl, err := template.New(path.Base(name)).ParseFS(files, "files/"+name, "files/partials/*.tmpl")
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+
+	if strings.HasSuffix(name, ".go.tmpl") {
+		formatted, err := format.Source(buf.Bytes())
+		if err != nil {
+			return "", fmt.Errorf("failed to format template %s: %w", name, err)
+		}
+		return string(formatted), nil
+	}
+	return buf.String(), nil
+}
+
+func renderFiles(names map[string]string, data any) (map[string]string, error) {
+	rendered := make(map[string]string, len(names))
+	for target, name := range names {
+		content, err := render(name, data)
+		if err != nil {
+			return nil, err
+		}
+		rendered[target] = content
+	}
+	return rendered, nil
+}
+
+// readFile returns files/<name> verbatim, for files such as Helm templates
+// that carry template syntax of their own.
+func readFile(name string) (string, error) {
+	content, err := files.ReadFile("files/" + name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+	return string(content), nil
+}
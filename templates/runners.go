@@ -0,0 +1,213 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+)
+
+var TaskRunners = []string{"make", "task", "just", "none"}
+
+// taskRunnerFiles names the file each runner reads.
+var taskRunnerFiles = map[string]string{
+	"make": "Makefile",
+	"task": "Taskfile.yml",
+	"just": "justfile",
+}
+
+// runnerSyntax translates the runner-neutral variables and commands of a
+// TaskSet into one runner's syntax.
+type runnerSyntax interface {
+	value(v TaskVar) string
+	cmd(cmd string) string
+	desc(desc string) string
+}
+
+// taskRunnerData is the task set with every variable, command and
+// description already in the runner's syntax.
+type taskRunnerData struct {
+	*config.Config
+	Vars  []TaskVar
+	Tasks []Task
+}
+
+// GetTaskRunnerTemplates renders the tasks of every enabled feature for
+// cfg.TaskRunner: a self-documenting Makefile, a Taskfile.yml or a
+// justfile. All three share BuildTasks, so their targets stay identical.
+func (tg *TemplateGenerator) GetTaskRunnerTemplates(cfg *config.Config) (map[string]string, error) {
+	file, ok := taskRunnerFiles[cfg.TaskRunner]
+	if !ok {
+		return nil, fmt.Errorf("unsupported task runner %q", cfg.TaskRunner)
+	}
+
+	set := BuildTasks(cfg)
+	var syntax runnerSyntax
+	switch cfg.TaskRunner {
+	case "make":
+		syntax = makeSyntax{}
+	case "task":
+		syntax = taskSyntax{}
+	case "just":
+		declared := make(map[string]bool, len(set.Vars))
+		for _, v := range set.Vars {
+			declared[v.Name] = true
+		}
+		syntax = justSyntax{declared: declared}
+	}
+
+	data := taskRunnerData{Config: cfg}
+	for _, v := range set.Vars {
+		data.Vars = append(data.Vars, TaskVar{Name: v.Name, Value: syntax.value(v)})
+	}
+	for _, t := range set.Tasks {
+		cmds := make([]string, len(t.Cmds))
+		for i, cmd := range t.Cmds {
+			cmds[i] = syntax.cmd(cmd)
+		}
+		data.Tasks = append(data.Tasks, Task{Name: t.Name, Desc: syntax.desc(t.Desc), Deps: t.Deps, Cmds: cmds})
+	}
+	return renderFiles(map[string]string{file: "tasks/" + file + ".tmpl"}, data)
+}
+
+var taskVarRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// replaceVars rewrites every ${NAME} with ref(NAME) and passes the text in
+// between through literal.
+func replaceVars(s string, ref, literal func(string) string) string {
+	var b strings.Builder
+	for len(s) > 0 {
+		loc := taskVarRef.FindStringSubmatchIndex(s)
+		if loc == nil {
+			b.WriteString(literal(s))
+			break
+		}
+		b.WriteString(literal(s[:loc[0]]))
+		b.WriteString(ref(s[loc[2]:loc[3]]))
+		s = s[loc[1]:]
+	}
+	return b.String()
+}
+
+func identity(s string) string { return s }
+
+// makeSyntax writes recipes where ${NAME} becomes $(NAME) and every other $
+// is doubled so make hands it to the shell.
+type makeSyntax struct{}
+
+func (makeSyntax) expand(s string) string {
+	return replaceVars(s,
+		func(name string) string { return "$(" + name + ")" },
+		func(text string) string { return strings.ReplaceAll(text, "$", "$$") })
+}
+
+func (m makeSyntax) value(v TaskVar) string {
+	if v.Shell != "" {
+		return "$(shell " + m.expand(v.Shell) + ")"
+	}
+	return m.expand(v.Value)
+}
+
+func (m makeSyntax) cmd(cmd string) string {
+	return strings.ReplaceAll(m.expand(cmd), "\n", "\n\t")
+}
+
+func (makeSyntax) desc(desc string) string { return desc }
+
+// taskSyntax writes Taskfile.yml values, where ${NAME} becomes {{.NAME}}.
+// Task exposes environment variables, including those from dotenv, under
+// the same names.
+type taskSyntax struct{}
+
+func (taskSyntax) expand(s string) string {
+	return replaceVars(s, func(name string) string { return "{{." + name + "}}" }, identity)
+}
+
+func (t taskSyntax) value(v TaskVar) string {
+	if v.Shell != "" {
+		return "\n    sh: " + yamlScalar(t.expand(v.Shell))
+	}
+	return " " + yamlScalar(t.expand(v.Value))
+}
+
+// cmd returns a list item body. Continued commands become literal blocks
+// indented to sit under the "      - " of the template.
+func (t taskSyntax) cmd(cmd string) string {
+	cmd = t.expand(cmd)
+	if !strings.Contains(cmd, "\n") {
+		return yamlScalar(cmd)
+	}
+	return "|\n        " + strings.ReplaceAll(cmd, "\n", "\n        ")
+}
+
+func (taskSyntax) desc(desc string) string { return yamlScalar(desc) }
+
+// justSyntax writes justfile recipes. Declared variables are interpolated
+// as {{NAME}}; anything else is left to the shell, which sees .env through
+// dotenv-load.
+type justSyntax struct {
+	declared map[string]bool
+}
+
+func (j justSyntax) value(v TaskVar) string {
+	if v.Shell != "" {
+		return "`" + v.Shell + "`"
+	}
+	var parts []string
+	literal := func(text string) string {
+		if text != "" {
+			parts = append(parts, justString(text))
+		}
+		return ""
+	}
+	ref := func(name string) string {
+		if j.declared[name] {
+			parts = append(parts, name)
+		} else {
+			parts = append(parts, `env("`+name+`", "")`)
+		}
+		return ""
+	}
+	replaceVars(v.Value, ref, literal)
+	if len(parts) == 0 {
+		return "''"
+	}
+	return strings.Join(parts, " + ")
+}
+
+func (j justSyntax) cmd(cmd string) string {
+	cmd = replaceVars(cmd, func(name string) string {
+		if j.declared[name] {
+			return "{{" + name + "}}"
+		}
+		return "${" + name + "}"
+	}, func(text string) string { return strings.ReplaceAll(text, "{{", "{{{{") })
+	return strings.ReplaceAll(cmd, "\n", "\n    ")
+}
+
+func (justSyntax) desc(desc string) string { return desc }
+
+// justString quotes s as a just string literal, preferring raw single
+// quotes.
+func justString(s string) string {
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'"
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// yamlScalar leaves s as a plain YAML scalar when that is unambiguous and
+// single-quotes it otherwise.
+func yamlScalar(s string) string {
+	plain := s != "" &&
+		!strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") &&
+		!strings.Contains(s, ": ") &&
+		!strings.Contains(s, " #") &&
+		!strings.HasSuffix(s, ":") &&
+		!strings.HasSuffix(s, " ")
+	if plain {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,101 @@
+package templates
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+)
+
+// taskNamePatterns finds the task definitions in each runner's file.
+var taskNamePatterns = map[string]*regexp.Regexp{
+	"make": regexp.MustCompile(`(?m)^([a-zA-Z0-9_-]+):.* ## `),
+	"task": regexp.MustCompile(`(?m)^  ([a-zA-Z0-9_-]+):$`),
+	"just": regexp.MustCompile(`(?m)^([a-zA-Z0-9_-]+):`),
+}
+
+// runnerDefaults are the listing tasks every runner adds on its own.
+var runnerDefaults = map[string]string{"make": "help", "task": "default", "just": "default"}
+
+func TestTaskRunnersDefineTheSameTasks(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.Config
+		want []string
+	}{
+		{
+			name: "web with every feature",
+			cfg: &config.Config{
+				ModuleName: "example.com/acme/api", AppType: "web", Framework: "chi",
+				UseAir: true, LintPreset: "recommended", UseSwaggo: true,
+				UseDocker: true, UseCompose: true, Deploy: "helm",
+				SelectedDependencies: []string{
+					"gorm.io/driver/postgres",
+					"github.com/golang-migrate/migrate/v4",
+					"github.com/sqlc-dev/sqlc",
+				},
+			},
+			want: []string{
+				"build", "run", "test", "dev", "lint", "spec", "spec-validate", "sqlc",
+				"migrate-new", "migrate-up", "migrate-down", "docker-build", "docker-run",
+				"up", "down", "logs", "manifests",
+			},
+		},
+		{
+			name: "grpc",
+			cfg: &config.Config{
+				ModuleName: "example.com/acme/greeter", AppType: "grpc",
+				LintPreset: "minimal", UseDocker: true,
+			},
+			want: []string{"build", "test", "lint", "proto", "docker-build", "docker-run"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want []string
+			for _, task := range BuildTasks(tt.cfg).Tasks {
+				want = append(want, task.Name)
+			}
+			for _, name := range tt.want {
+				if !slices.Contains(want, name) {
+					t.Fatalf("BuildTasks has no %s task: %v", name, want)
+				}
+			}
+
+			for _, runner := range TaskRunners {
+				if runner == "none" {
+					continue
+				}
+				cfg := *tt.cfg
+				cfg.TaskRunner = runner
+				files, err := (&TemplateGenerator{}).GetTaskRunnerTemplates(&cfg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				content := files[taskRunnerFiles[runner]]
+				if runner == "task" {
+					// Variables sit at the same depth as tasks.
+					_, content, _ = strings.Cut(content, "\ntasks:\n")
+				}
+				var got []string
+				for _, m := range taskNamePatterns[runner].FindAllStringSubmatch(content, -1) {
+					if m[1] != runnerDefaults[runner] {
+						got = append(got, m[1])
+					}
+				}
+				if !slices.Equal(got, want) {
+					t.Errorf("%s tasks = %s, want %s", runner, strings.Join(got, " "), strings.Join(want, " "))
+				}
+			}
+		})
+	}
+}
+
+func TestUnknownTaskRunner(t *testing.T) {
+	cfg := &config.Config{ModuleName: "example.com/acme/api", AppType: "web", Framework: "gin", TaskRunner: "mage"}
+	if _, err := (&TemplateGenerator{}).GetTaskRunnerTemplates(cfg); err == nil || !strings.Contains(err.Error(), `unsupported task runner "mage"`) {
+		t.Errorf("error = %v, want an unsupported runner error", err)
+	}
+}
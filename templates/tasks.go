@@ -0,0 +1,272 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+)
+
+// Task is one target of the generated task runner. Commands are shell
+// lines, continued with a trailing backslash and newline: a single $
+// belongs to the shell and ${NAME} refers to a TaskVar or a variable from
+// the environment, so each runner can translate them into its own syntax.
+type Task struct {
+	Name string
+	Desc string
+	Deps []string
+	Cmds []string
+}
+
+// TaskVar is a variable the tasks share. Shell takes precedence over Value
+// and is run to compute the default; either way the user can override it.
+type TaskVar struct {
+	Name  string
+	Value string
+	Shell string
+}
+
+// TaskSet is what a feature contributes: its variables and its tasks.
+type TaskSet struct {
+	Vars  []TaskVar
+	Tasks []Task
+}
+
+// BuildTasks assembles the task sets of every feature cfg enables, in the
+// order they appear in the generated file.
+func BuildTasks(cfg *config.Config) TaskSet {
+	fragments := []struct {
+		enabled bool
+		tasks   func(*config.Config) TaskSet
+	}{
+		{true, goTasks},
+		{cfg.UseAir && !cfg.IsLibrary(), devTasks},
+		{cfg.UsesLinter(), lintTasks},
+		{cfg.AppType == "grpc", protoTasks},
+		{cfg.HasAPIDocs(), specTasks},
+		{cfg.UsesSQLC(), sqlcTasks},
+		{cfg.UsesMigrations(), migrateTasks},
+		{cfg.UseDocker && !cfg.IsLibrary(), dockerTasks},
+		{cfg.HasCompose(), composeTasks},
+		{cfg.UsesDeploy(), deployTasks},
+	}
+
+	var set TaskSet
+	for _, fragment := range fragments {
+		if fragment.enabled {
+			part := fragment.tasks(cfg)
+			set.Vars = append(set.Vars, part.Vars...)
+			set.Tasks = append(set.Tasks, part.Tasks...)
+		}
+	}
+	return set
+}
+
+func goTasks(cfg *config.Config) TaskSet {
+	check := []string{"fmt", "vet"}
+	if cfg.UsesLinter() {
+		check = append(check, "lint")
+	}
+	check = append(check, "test")
+
+	if cfg.IsLibrary() {
+		return TaskSet{Tasks: []Task{
+			{Name: "all", Desc: "Format, vet" + lintDesc(cfg) + " and test", Deps: check},
+			{Name: "test", Desc: "Run the tests", Cmds: []string{"go test ./..."}},
+			{Name: "race", Desc: "Run the tests with the race detector", Cmds: []string{"go test -race ./..."}},
+			{Name: "bench", Desc: "Run the benchmarks", Cmds: []string{"go test -run='^$' -bench=. -benchmem ./..."}},
+			coverTask(),
+			fmtTask(),
+			{Name: "vet", Desc: "Run go vet", Cmds: []string{"go vet ./..."}},
+			tidyTask(cfg),
+			{Name: "clean", Desc: "Remove coverage output", Cmds: []string{"rm -f " + strings.Join(CoverageFiles, " ")}},
+		}}
+	}
+
+	return TaskSet{
+		Vars: []TaskVar{
+			{Name: "APP_NAME", Value: cfg.ProjectName()},
+			{Name: "VERSION", Shell: "git describe --tags --always --dirty 2>/dev/null || echo dev"},
+			{Name: "COMMIT", Shell: "git rev-parse --short HEAD 2>/dev/null || echo none"},
+			{Name: "LDFLAGS", Value: "-s -w -X main.version=${VERSION} -X main.commit=${COMMIT}"},
+		},
+		Tasks: []Task{
+			{Name: "all", Desc: "Format, vet" + lintDesc(cfg) + ", test and build", Deps: append(check, "build")},
+			{Name: "build", Desc: "Build the binary into bin/ with version information", Cmds: []string{
+				`go build -trimpath -ldflags "${LDFLAGS}" -o ` + BinDir + `/${APP_NAME} ./cmd`,
+			}},
+			{Name: "run", Desc: "Run the application", Cmds: []string{`go run -ldflags "${LDFLAGS}" ./cmd`}},
+			{Name: "test", Desc: "Run the tests", Cmds: []string{"go test ./..."}},
+			{Name: "race", Desc: "Run the tests with the race detector", Cmds: []string{"go test -race ./..."}},
+			coverTask(),
+			fmtTask(),
+			{Name: "vet", Desc: "Run go vet", Cmds: []string{"go vet ./..."}},
+			tidyTask(cfg),
+			{Name: "clean", Desc: "Remove build and coverage output", Cmds: []string{"rm -rf " + BinDir + "/ tmp/ " + strings.Join(CoverageFiles, " ")}},
+		},
+	}
+}
+
+func lintDesc(cfg *config.Config) string {
+	if cfg.UsesLinter() {
+		return ", lint"
+	}
+	return ""
+}
+
+// BinDir receives the binaries the build task compiles, and CoverageFiles
+// are the profile and report of the cover task. .gitignore lists both.
+const BinDir = "bin"
+
+var CoverageFiles = []string{"coverage.out", "coverage.html"}
+
+func coverTask() Task {
+	profile, report := CoverageFiles[0], CoverageFiles[1]
+	return Task{Name: "cover", Desc: "Run the tests with coverage and write " + report, Cmds: []string{
+		"go test -coverprofile=" + profile + " ./...",
+		"go tool cover -func=" + profile + " | tail -1",
+		"go tool cover -html=" + profile + " -o " + report,
+	}}
+}
+
+func tidyTask(cfg *config.Config) Task {
+	if cfg.UseVendor {
+		return Task{Name: "tidy", Desc: "Tidy go.mod and go.sum and refresh vendor/", Cmds: []string{"go mod tidy", "go mod vendor"}}
+	}
+	return Task{Name: "tidy", Desc: "Tidy go.mod and go.sum", Cmds: []string{"go mod tidy"}}
+}
+
+func fmtTask() Task {
+	return Task{Name: "fmt", Desc: "Format the code with gofmt -s", Cmds: []string{
+		`gofmt -s -w $(find . -name '*.go' -not -path './vendor/*')`,
+	}}
+}
+
+// AirCommand runs air against ./cmd, which the .air.toml from air init
+// does not build.
+const AirCommand = `air --build.cmd "go build -o ./tmp/main ./cmd" --build.bin ./tmp/main`
+
+func devTasks(cfg *config.Config) TaskSet {
+	return TaskSet{Tasks: []Task{
+		{Name: "dev", Desc: "Run with hot reload (air)", Cmds: []string{
+			AirCommand,
+		}},
+	}}
+}
+
+func lintTasks(cfg *config.Config) TaskSet {
+	return TaskSet{Tasks: []Task{
+		{Name: "lint", Desc: "Run golangci-lint (version pinned in go.mod)", Cmds: []string{"go tool golangci-lint run"}},
+	}}
+}
+
+func protoTasks(cfg *config.Config) TaskSet {
+	return TaskSet{Tasks: []Task{
+		{Name: "proto", Desc: "Generate gRPC stubs into api/gen", Cmds: []string{
+			"if command -v buf >/dev/null 2>&1; then \\\n" +
+				"  buf generate; \\\n" +
+				"else \\\n" +
+				"  mkdir -p api/gen && \\\n" +
+				"  go build -o bin/ google.golang.org/protobuf/cmd/protoc-gen-go google.golang.org/grpc/cmd/protoc-gen-go-grpc && \\\n" +
+				"  protoc -I api/proto \\\n" +
+				"    --plugin=protoc-gen-go=bin/protoc-gen-go --go_out=api/gen --go_opt=paths=source_relative \\\n" +
+				"    --plugin=protoc-gen-go-grpc=bin/protoc-gen-go-grpc --go-grpc_out=api/gen --go-grpc_opt=paths=source_relative \\\n" +
+				"    $(find api/proto -name '*.proto'); \\\n" +
+				"fi",
+		}},
+	}}
+}
+
+func specTasks(cfg *config.Config) TaskSet {
+	var tasks []Task
+	if cfg.UseSwaggo {
+		tasks = append(tasks, Task{Name: "spec", Desc: "Generate api/" + cfg.SpecFile() + " from swaggo annotations", Cmds: []string{"go generate ./api"}})
+	}
+	tasks = append(tasks, Task{Name: "spec-validate", Desc: "Validate api/" + cfg.SpecFile(), Cmds: []string{"go test -count=1 -run TestSpecIsValid ./api"}})
+	return TaskSet{Tasks: tasks}
+}
+
+func sqlcTasks(cfg *config.Config) TaskSet {
+	return TaskSet{Tasks: []Task{
+		{Name: "sqlc", Desc: "Generate type-safe queries from db/queries", Cmds: []string{"go tool sqlc generate"}},
+	}}
+}
+
+func migrateTasks(cfg *config.Config) TaskSet {
+	tag, scheme := migrateDriver(cfg.Database())
+	return TaskSet{
+		Vars: []TaskVar{
+			{Name: "MIGRATE", Value: "go run -tags " + tag + " " + migrateCommand},
+			{Name: "MIGRATE_URL", Value: scheme + "${DATABASE_URL}"},
+			{Name: "NAME", Value: "change_me"},
+		},
+		Tasks: []Task{
+			{Name: "migrate-new", Desc: "Create an empty migration pair, e.g. NAME=add_users", Cmds: []string{
+				"${MIGRATE} create -ext sql -dir " + MigrationsDir + " -seq ${NAME}",
+			}},
+			{Name: "migrate-up", Desc: "Apply all pending migrations to DATABASE_URL", Cmds: []string{
+				`${MIGRATE} -path ` + MigrationsDir + ` -database "${MIGRATE_URL}" up`,
+			}},
+			{Name: "migrate-down", Desc: "Roll back the last migration", Cmds: []string{
+				`${MIGRATE} -path ` + MigrationsDir + ` -database "${MIGRATE_URL}" down 1`,
+			}},
+		},
+	}
+}
+
+// MigrationsDir holds the golang-migrate files; sqlc reads its schema from
+// there as well.
+const MigrationsDir = "db/migrations"
+
+const migrateCommand = "github.com/golang-migrate/migrate/v4/cmd/migrate"
+
+// migrateDriver returns the build tag that compiles the golang-migrate
+// driver for db and the URL scheme to put in front of DATABASE_URL.
+func migrateDriver(db string) (tag, scheme string) {
+	switch db {
+	case "mysql":
+		return "mysql", "mysql://"
+	case "sqlite":
+		return "sqlite3", "sqlite3://"
+	}
+	return "postgres", ""
+}
+
+func dockerTasks(cfg *config.Config) TaskSet {
+	run := "docker run --rm ${DOCKER_IMAGE}"
+	if cfg.IsService() {
+		port := fmt.Sprint(cfg.DefaultPort())
+		run = "docker run --rm -p " + port + ":" + port + " --env-file .env ${DOCKER_IMAGE}"
+	}
+	return TaskSet{
+		Vars: []TaskVar{{Name: "DOCKER_IMAGE", Value: "${APP_NAME}:${VERSION}"}},
+		Tasks: []Task{
+			{Name: "docker-build", Desc: "Build the Docker image", Cmds: []string{
+				"docker build --build-arg VERSION=${VERSION} --build-arg COMMIT=${COMMIT} -t ${DOCKER_IMAGE} .",
+			}},
+			{Name: "docker-run", Desc: "Run the Docker image", Deps: []string{"docker-build"}, Cmds: []string{run}},
+		},
+	}
+}
+
+func composeTasks(cfg *config.Config) TaskSet {
+	up := Task{Name: "up", Desc: "Start the backing services with docker compose", Cmds: []string{"docker compose up -d"}}
+	if cfg.UseCompose {
+		up = Task{Name: "up", Desc: "Build and start the app and its services with docker compose", Cmds: []string{"docker compose up -d --build"}}
+	}
+	return TaskSet{Tasks: []Task{
+		up,
+		{Name: "down", Desc: "Stop the compose stack", Cmds: []string{"docker compose down"}},
+		{Name: "logs", Desc: "Follow the compose logs", Cmds: []string{"docker compose logs -f"}},
+	}}
+}
+
+func deployTasks(cfg *config.Config) TaskSet {
+	render := "kubectl kustomize deploy/k8s"
+	if cfg.Deploy == "helm" {
+		render = "helm template " + cfg.DNSName() + " deploy/helm/" + cfg.DNSName() + " --set image.tag=${VERSION}"
+	}
+	return TaskSet{Tasks: []Task{
+		{Name: "manifests", Desc: "Render the Kubernetes manifests in deploy/ to stdout", Cmds: []string{render}},
+	}}
+}
@@ -0,0 +1,81 @@
+package templates
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+)
+
+var ldflagVar = regexp.MustCompile(`-X main\.(\w+)=`)
+
+// mainVars returns the package-level variables of a rendered main.go.
+func mainVars(t *testing.T, src string) map[string]bool {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "main.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]bool{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				vars[name.Name] = true
+			}
+		}
+	}
+	return vars
+}
+
+// expectStampedVars fails unless every -X main.name in flags names a
+// variable that main declares; the linker silently ignores the others.
+func expectStampedVars(t *testing.T, cfg *config.Config, flags string) {
+	t.Helper()
+	matches := ldflagVar.FindAllStringSubmatch(flags, -1)
+	if len(matches) == 0 {
+		t.Fatalf("no -X flags in %q", flags)
+	}
+	main, err := (&TemplateGenerator{}).GetMainTemplate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := mainVars(t, main)
+	for _, m := range matches {
+		if !vars[m[1]] {
+			t.Errorf("%s stamps main.%s, which main.go does not declare", flags, m[1])
+		}
+	}
+}
+
+// appTypeConfigs has one config per app type with a main package.
+func appTypeConfigs() []*config.Config {
+	return []*config.Config{
+		{ModuleName: "example.com/acme/web", AppType: "web", Framework: "gin"},
+		{ModuleName: "example.com/acme/gql", AppType: "graphql", Framework: "chi"},
+		{ModuleName: "example.com/acme/greeter", AppType: "grpc"},
+		{ModuleName: "example.com/acme/jobs", AppType: "worker"},
+		{ModuleName: "example.com/acme/tool", AppType: "cli"},
+		{ModuleName: "example.com/acme/ctl", AppType: "cobra"},
+	}
+}
+
+func TestTaskLDFlagsStampDeclaredVars(t *testing.T) {
+	for _, cfg := range appTypeConfigs() {
+		t.Run(cfg.AppType, func(t *testing.T) {
+			var flags string
+			for _, v := range BuildTasks(cfg).Vars {
+				if v.Name == "LDFLAGS" {
+					flags = v.Value
+				}
+			}
+			expectStampedVars(t, cfg, flags)
+		})
+	}
+}
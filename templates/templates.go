@@ -1,294 +1,383 @@
 package templates
 
-type TemplateGenerator struct{}
-
-func (tg *TemplateGenerator) GetMainTemplate(appType, framework string) string {
-	switch appType {
-	case "cli":
-		return tg.GetCliMainTemplate()
-	case "cobra":
-		return tg.GetCobraMainTemplate()
-	case "web":
-		return tg.GetWebMainTemplate(framework)
-	}
-	return ""
-}
-
-func (tg *TemplateGenerator) GetCliMainTemplate() string {
-	return `package main
-
 import (
 	"fmt"
-	"os"
+	"slices"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+	"github.com/SwanHtetAungPhyo/gostart/openapi"
 )
 
-func main() {
-	fmt.Println("Hello CLI Application!")
-	
-	if len(os.Args) > 1 {
-		fmt.Printf("Arguments: %v\n", os.Args[1:])
-	}
-}`
-}
+type TemplateGenerator struct{}
 
-func (tg *TemplateGenerator) GetCobraMainTemplate() string {
-	return `package main
+var WebFrameworks = []string{"fiber", "gin", "echo", "chi", "gorilla", "stdlib"}
 
-import (
-	"fmt"
-	"os"
+var Loggers = []string{"slog", "zap", "zerolog", "logrus"}
 
-	"github.com/spf13/cobra"
-)
+var CIProviders = []string{"none", "github", "gitlab", "script"}
 
-var rootCmd = &cobra.Command{
-	Use:   "app",
-	Short: "A brief description of your application",
-	Long:  "A longer description of your application",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Hello Cobra Application!")
-	},
-}
+var LintPresets = []string{"recommended", "minimal", "strict", "none"}
 
-func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
+var DockerRuntimes = []string{"distroless", "alpine", "scratch"}
+
+var DeployTargets = []string{"none", "kubernetes", "helm"}
+
+var Editors = []string{"vscode", "goland", "vim", "none"}
+
+// ciData adds the release platforms the CI templates need on top of the
+// project configuration.
+type ciData struct {
+	*config.Config
+	ReleaseTargets string
 }
 
-func init() {
+// dockerData resolves the runtime image and builder Go version, which fall
+// back to distroless and Go 1.24 when the configuration leaves them empty.
+type dockerData struct {
+	*config.Config
+	Runtime        string
+	BuildGoVersion string
 }
 
-func main() {
-	Execute()
-}`
+// gitignoreData adds the build and coverage paths of the tasks, which
+// .gitignore has to match.
+type gitignoreData struct {
+	*config.Config
+	BinDir        string
+	CoverageFiles []string
 }
 
-func (tg *TemplateGenerator) GetWebMainTemplate(framework string) string {
-	switch framework {
-	case "fiber":
-		return tg.GetFiberMainTemplate()
-	case "gin":
-		return tg.GetGinMainTemplate()
-	default:
-		return tg.GetEchoMainTemplate()
+func (tg *TemplateGenerator) GetMainTemplate(cfg *config.Config) (string, error) {
+	switch cfg.AppType {
+	case "cli":
+		return render("cli/main.go.tmpl", cfg)
+	case "cobra":
+		return render("cobra/main.go.tmpl", cfg)
+	case "grpc":
+		return render("grpc/main.go.tmpl", cfg)
+	case "worker":
+		return render("worker/main.go.tmpl", cfg)
+	case "web", "graphql":
+		return tg.GetWebMainTemplate(cfg)
 	}
+	return "", fmt.Errorf("unknown app type %q", cfg.AppType)
 }
 
-func (tg *TemplateGenerator) GetFiberMainTemplate() string {
-	return `package main
-
-import (
-	"log"
-
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/logger"
-	"github.com/gofiber/fiber/v2/middleware/recover"
-)
-
-func main() {
-	app := fiber.New(fiber.Config{
-		AppName: "Go Fiber App",
-	})
-
-	app.Use(logger.New())
-	app.Use(recover.New())
-
-	app.Get("/", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"message": "Hello Fiber!",
-			"status":  "success",
-		})
-	})
-
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{"status": "healthy"})
-	})
-
-	log.Println("🚀 Server starting on :8080")
-	log.Fatal(app.Listen(":8080"))
-}`
+// GetCobraCommandTemplates returns the internal/cli command tree that the
+// cobra main wires up, keyed by project-relative path.
+func (tg *TemplateGenerator) GetCobraCommandTemplates(cfg *config.Config) (map[string]string, error) {
+	return renderFiles(map[string]string{
+		"internal/cli/root.go":       "cobra/root.go.tmpl",
+		"internal/cli/version.go":    "cobra/version.go.tmpl",
+		"internal/cli/completion.go": "cobra/completion.go.tmpl",
+		"internal/cli/greet.go":      "cobra/greet.go.tmpl",
+		"internal/cli/cli_test.go":   "cobra/cli_test.go.tmpl",
+	}, cfg)
 }
 
-func (tg *TemplateGenerator) GetGinMainTemplate() string {
-	return `package main
-
-import (
-	"net/http"
-
-	"github.com/gin-gonic/gin"
-)
-
-func main() {
-	r := gin.Default()
-
-	r.Use(gin.Logger())
-	r.Use(gin.Recovery())
-
-	r.GET("/", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Hello Gin!",
-			"status":  "success",
-		})
-	})
-
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
-	})
-
-	r.Run(":8080")
-}`
+// GetGRPCTemplates returns the example proto, buf configuration, service
+// implementation and client for the grpc app type, keyed by
+// project-relative path.
+func (tg *TemplateGenerator) GetGRPCTemplates(cfg *config.Config) (map[string]string, error) {
+	return renderFiles(map[string]string{
+		"api/proto/" + cfg.ProtoPackage() + "/v1/greeter.proto": "grpc/greeter.proto.tmpl",
+		"buf.yaml":                    "grpc/buf.yaml.tmpl",
+		"buf.gen.yaml":                "grpc/buf.gen.yaml.tmpl",
+		"internal/handler/greeter.go": "grpc/greeter.go.tmpl",
+		"cmd/client/main.go":          "grpc/client.go.tmpl",
+	}, cfg)
 }
 
-func (tg *TemplateGenerator) GetEchoMainTemplate() string {
-	return `package main
-
-import (
-	"net/http"
-
-	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
-)
-
-func main() {
-	e := echo.New()
-
-	e.Use(middleware.Logger())
-	e.Use(middleware.Recover())
-
-	e.GET("/", func(c echo.Context) error {
-		return c.JSON(http.StatusOK, map[string]interface{}{
-			"message": "Hello Echo!",
-			"status":  "success",
-		})
-	})
-
-	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(http.StatusOK, map[string]string{"status": "healthy"})
-	})
-
-	e.Logger.Info(" Server starting on :8080")
-	e.Start(":8080")
-}`
+// GetGraphQLTemplates returns the gqlgen configuration, starter schema and
+// hand-written parts of internal/handler/graph, keyed by project-relative
+// path. The rest of the package comes from gqlgen generate.
+func (tg *TemplateGenerator) GetGraphQLTemplates(cfg *config.Config) (map[string]string, error) {
+	return renderFiles(map[string]string{
+		"gqlgen.yml":                                 "graphql/gqlgen.yml.tmpl",
+		"internal/handler/graph/schema.graphqls":     "graphql/schema.graphqls.tmpl",
+		"internal/handler/graph/resolver.go":         "graphql/resolver.go.tmpl",
+		"internal/handler/graph/schema.resolvers.go": "graphql/schema.resolvers.go.tmpl",
+		"internal/handler/graph/server.go":           "graphql/server.go.tmpl",
+	}, cfg)
 }
 
-func (tg *TemplateGenerator) GetDockerTemplate() string {
-	return `FROM golang:1.22-alpine AS builder
+// GetWorkerTemplates returns the internal/worker pool, job source and
+// optional scheduler plus the job handler and probe listener, keyed by
+// project-relative path.
+func (tg *TemplateGenerator) GetWorkerTemplates(cfg *config.Config) (map[string]string, error) {
+	files := map[string]string{
+		"internal/worker/job.go":       "worker/job.go.tmpl",
+		"internal/worker/memory.go":    "worker/memory.go.tmpl",
+		"internal/worker/pool.go":      "worker/pool.go.tmpl",
+		"internal/worker/pool_test.go": "worker/pool_test.go.tmpl",
+		"internal/handler/jobs.go":     "worker/jobs.go.tmpl",
+		"internal/handler/probes.go":   "worker/probes.go.tmpl",
+	}
+	if cfg.UseScheduler {
+		files["internal/worker/scheduler.go"] = "worker/scheduler.go.tmpl"
+	}
+	if !cfg.UsesStore() {
+		// With a store, readiness depends on a live database.
+		files["internal/handler/probes_test.go"] = "worker/probes_test.go.tmpl"
+	}
+	return renderFiles(files, cfg)
+}
 
-WORKDIR /app
+// GetLibraryTemplates returns the root package of a library project with
+// its package doc, example, tests and testdata, keyed by project-relative
+// path.
+func (tg *TemplateGenerator) GetLibraryTemplates(cfg *config.Config) (map[string]string, error) {
+	name := cfg.PackageName()
+	return renderFiles(map[string]string{
+		"doc.go":               "library/doc.go.tmpl",
+		name + ".go":           "library/library.go.tmpl",
+		name + "_test.go":      "library/library_test.go.tmpl",
+		"example_test.go":      "library/example_test.go.tmpl",
+		"testdata/slugify.txt": "library/slugify.txt.tmpl",
+	}, cfg)
+}
 
-RUN apk add --no-cache git
+func (tg *TemplateGenerator) GetWebMainTemplate(cfg *config.Config) (string, error) {
+	if !isWebFramework(cfg.Framework) {
+		return "", fmt.Errorf("unknown web framework %q", cfg.Framework)
+	}
+	return render("web/"+cfg.Framework+"/main.go.tmpl", cfg)
+}
 
-COPY go.mod go.sum ./
-RUN go mod download
+// GetExampleResourceTemplates returns the todo slice that shows how model,
+// repository, service and handler connect, keyed by project-relative path.
+func (tg *TemplateGenerator) GetExampleResourceTemplates(cfg *config.Config) (map[string]string, error) {
+	if !isWebFramework(cfg.Framework) {
+		return nil, fmt.Errorf("unknown web framework %q", cfg.Framework)
+	}
 
-COPY . .
+	files := map[string]string{
+		"internal/model/todo.go":      "example/model.go.tmpl",
+		"internal/repository/todo.go": "example/repository.go.tmpl",
+		"internal/service/todo.go":    "example/service.go.tmpl",
+	}
+	files["internal/handler/todo.go"] = frameworkTemplate(cfg.Framework, "todo_handler.go.tmpl")
+	return renderFiles(files, cfg)
+}
 
-RUN CGO_ENABLED=0 GOOS=linux go build -a -installsuffix cgo -o server ./cmd
+// GetRouterTemplates returns the NewRouter constructor main wires up, its
+// dependencies and the httptest suite covering / and /health.
+func (tg *TemplateGenerator) GetRouterTemplates(cfg *config.Config) (map[string]string, error) {
+	if !isWebFramework(cfg.Framework) {
+		return nil, fmt.Errorf("unknown web framework %q", cfg.Framework)
+	}
 
-FROM alpine:latest
+	files := map[string]string{
+		"internal/handler/router.go":       "web/" + cfg.Framework + "/router.go.tmpl",
+		"internal/handler/dependencies.go": "web/dependencies.go.tmpl",
+		"internal/handler/router_test.go":  "web/router_test.go.tmpl",
+	}
+	if usesNetHTTP(cfg.Framework) {
+		files["internal/handler/response.go"] = "web/nethttp/response.go.tmpl"
+		files["internal/handler/health.go"] = "web/nethttp/health.go.tmpl"
+	}
+	return renderFiles(files, cfg)
+}
 
-RUN apk --no-cache add ca-certificates tzdata
+// GetAPIDocsTemplates returns the api package embedding the OpenAPI
+// document, its validation test and the /docs handler. With swaggo the
+// document itself is generated by swag, and with --from-openapi it is the
+// user's, so neither is rendered here.
+func (tg *TemplateGenerator) GetAPIDocsTemplates(cfg *config.Config) (map[string]string, error) {
+	files := map[string]string{
+		"api/api.go":               "api/api.go.tmpl",
+		"api/api_test.go":          "api/api_test.go.tmpl",
+		"internal/handler/docs.go": "api/docs.go.tmpl",
+	}
+	if !cfg.UseSwaggo && cfg.OpenAPISpec == "" {
+		files["api/openapi.yaml"] = "api/openapi.yaml.tmpl"
+	}
+	return renderFiles(files, cfg)
+}
 
-WORKDIR /root/
+type openAPIData struct {
+	*config.Config
+	API *openapi.API
+}
 
-COPY --from=builder /app/server .
+// GetOpenAPITemplates returns the code generated from an OpenAPI document.
+// generated holds the *.gen.go files, which are rewritten on every run;
+// stubs holds internal/handler/api.go, which is only written once.
+func (tg *TemplateGenerator) GetOpenAPITemplates(cfg *config.Config, api *openapi.API) (generated, stubs map[string]string, err error) {
+	if !isWebFramework(cfg.Framework) {
+		return nil, nil, fmt.Errorf("unknown web framework %q", cfg.Framework)
+	}
 
-EXPOSE 8080
+	routes := "openapi/" + cfg.Framework + "/routes.go.tmpl"
+	if usesNetHTTP(cfg.Framework) {
+		routes = "openapi/nethttp/routes.go.tmpl"
+	}
+	data := openAPIData{Config: cfg, API: api}
+	generated, err = renderFiles(map[string]string{
+		"internal/model/openapi.gen.go":          "openapi/model.go.tmpl",
+		"internal/handler/openapi.gen.go":        "openapi/handler.go.tmpl",
+		"internal/handler/openapi_routes.gen.go": routes,
+	}, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	stubs, err = renderFiles(map[string]string{
+		"internal/handler/api.go": "openapi/api.go.tmpl",
+	}, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return generated, stubs, nil
+}
 
-CMD ["./server"]`
+func (tg *TemplateGenerator) GetConfigTemplate(cfg *config.Config) (string, error) {
+	return render("config/config.go.tmpl", cfg)
 }
 
-func (tg *TemplateGenerator) GetMakefileTemplate() string {
-	return `.PHONY: all build run test clean fmt vet lint tidy docker-build docker-run help
+// GetStoreTemplates returns the connection setup for the selected backing
+// services and the example model it migrates, keyed by project-relative path.
+func (tg *TemplateGenerator) GetStoreTemplates(cfg *config.Config) (map[string]string, error) {
+	files := map[string]string{
+		"internal/repository/store.go": "database/store.go.tmpl",
+	}
+	if cfg.UsesSQL() {
+		files["internal/model/todo.go"] = "example/model.go.tmpl"
+	}
+	return renderFiles(files, cfg)
+}
 
-APP_NAME ?= server
-DOCKER_IMAGE ?= $(APP_NAME):latest
-GO_FILES := $(shell find . -type f -name '*.go' -not -path "./vendor/*")
+// GetComposeTemplate returns a compose.yaml with the backing services and,
+// when UseCompose is set, the app itself plus an air-based dev profile.
+func (tg *TemplateGenerator) GetComposeTemplate(cfg *config.Config) (string, error) {
+	return render("compose/compose.yaml.tmpl", cfg)
+}
 
-all: fmt vet lint test build
+func (tg *TemplateGenerator) GetServerTemplate(cfg *config.Config) (string, error) {
+	return render("server/server.go.tmpl", cfg)
+}
 
-build:
-	@echo "🔨 Building application..."
-	@go build -o bin/$(APP_NAME) ./cmd
+// GetLoggerTemplates returns internal/logger for the chosen library and the
+// framework's request logging middleware (gRPC interceptors for the grpc app
+// type, nothing for workers), keyed by project-relative path.
+func (tg *TemplateGenerator) GetLoggerTemplates(cfg *config.Config) (map[string]string, error) {
+	library := cfg.Logger
+	if library == "" {
+		library = "slog"
+	}
+	if !slices.Contains(Loggers, library) {
+		return nil, fmt.Errorf("unknown logging library %q", library)
+	}
+	switch cfg.AppType {
+	case "grpc":
+		return renderFiles(map[string]string{
+			"internal/logger/logger.go":       "logger/" + library + ".go.tmpl",
+			"internal/handler/interceptor.go": "grpc/interceptor.go.tmpl",
+		}, cfg)
+	case "worker":
+		return renderFiles(map[string]string{
+			"internal/logger/logger.go": "logger/" + library + ".go.tmpl",
+		}, cfg)
+	}
+	if !isWebFramework(cfg.Framework) {
+		return nil, fmt.Errorf("unknown web framework %q", cfg.Framework)
+	}
 
-run:
-	@echo "🚀 Running application..."
-	@go run ./cmd
+	return renderFiles(map[string]string{
+		"internal/logger/logger.go":      "logger/" + library + ".go.tmpl",
+		"internal/handler/middleware.go": frameworkTemplate(cfg.Framework, "middleware.go.tmpl"),
+	}, cfg)
+}
 
-test:
-	@echo "🧪 Running tests..."
-	@go test -v ./...
+func (tg *TemplateGenerator) GetEnvExampleTemplate(cfg *config.Config) (string, error) {
+	return render("config/env.example.tmpl", cfg)
+}
 
-test-coverage:
-	@echo "🧪 Running tests with coverage..."
-	@go test -v -coverprofile=coverage.out ./...
-	@go tool cover -html=coverage.out -o coverage.html
+func isWebFramework(framework string) bool {
+	return slices.Contains(WebFrameworks, framework)
+}
 
-clean:
-	@echo "🧹 Cleaning..."
-	@rm -rf bin/
-	@rm -f coverage.out coverage.html
+// usesNetHTTP reports whether handlers are plain http.HandlerFuncs, in
+// which case chi, gorilla and stdlib share the templates under web/nethttp.
+func usesNetHTTP(framework string) bool {
+	switch framework {
+	case "chi", "gorilla", "stdlib":
+		return true
+	}
+	return false
+}
 
-fmt:
-	@echo "📝 Formatting code..."
-	@gofmt -s -w $(GO_FILES)
+func frameworkTemplate(framework, name string) string {
+	if usesNetHTTP(framework) {
+		return "web/nethttp/" + name
+	}
+	return "web/" + framework + "/" + name
+}
 
-vet:
-	@echo "🔍 Running go vet..."
-	@go vet ./...
+// GetDockerTemplates renders the Dockerfile and .dockerignore, plus the
+// probe binary behind HEALTHCHECK for apps that serve /health.
+func (tg *TemplateGenerator) GetDockerTemplates(cfg *config.Config) (map[string]string, error) {
+	data := dockerData{Config: cfg, Runtime: cfg.DockerRuntime, BuildGoVersion: cfg.GoVersion}
+	if data.Runtime == "" {
+		data.Runtime = "distroless"
+	}
+	if !slices.Contains(DockerRuntimes, data.Runtime) {
+		return nil, fmt.Errorf("unsupported Docker runtime %q", data.Runtime)
+	}
+	if data.BuildGoVersion == "" {
+		data.BuildGoVersion = "1.24"
+	}
 
-lint:
-	@echo "🔍 Running linter..."
-	@golangci-lint run
+	names := map[string]string{
+		"Dockerfile":    "docker/Dockerfile.tmpl",
+		".dockerignore": "docker/dockerignore.tmpl",
+	}
+	if cfg.HasHealthEndpoint() {
+		names["cmd/healthcheck/main.go"] = "docker/healthcheck.go.tmpl"
+	}
+	return renderFiles(names, data)
+}
 
-tidy:
-	@echo "📦 Tidying go modules..."
-	@go mod tidy
+// GetSQLCTemplate renders sqlc.yaml for the selected SQL engine.
+func (tg *TemplateGenerator) GetSQLCTemplate(cfg *config.Config) (string, error) {
+	return render("database/sqlc.yaml.tmpl", cfg)
+}
 
-docker-build:
-	@echo "🐳 Building Docker image..."
-	@docker build -t $(DOCKER_IMAGE) .
+// GetCITemplates renders the pipeline for cfg.CI: a GitHub Actions
+// workflow, a .gitlab-ci.yml, or a portable scripts/ci.sh.
+func (tg *TemplateGenerator) GetCITemplates(cfg *config.Config) (map[string]string, error) {
+	var names map[string]string
+	switch cfg.CI {
+	case "github":
+		names = map[string]string{".github/workflows/ci.yml": "ci/github.yml.tmpl"}
+	case "gitlab":
+		names = map[string]string{".gitlab-ci.yml": "ci/gitlab-ci.yml.tmpl"}
+	case "script":
+		names = map[string]string{"scripts/ci.sh": "ci/ci.sh.tmpl"}
+	default:
+		return nil, fmt.Errorf("unsupported CI provider %q", cfg.CI)
+	}
+	return renderFiles(names, ciData{
+		Config:         cfg,
+		ReleaseTargets: "linux/amd64 linux/arm64 darwin/amd64 darwin/arm64 windows/amd64",
+	})
+}
 
-docker-run:
-	@echo "🐳 Running Docker container..."
-	@docker run -p 8080:8080 $(DOCKER_IMAGE)
+// GetLintTemplate renders .golangci.yml for cfg.LintPreset.
+func (tg *TemplateGenerator) GetLintTemplate(cfg *config.Config) (string, error) {
+	if !slices.Contains(LintPresets, cfg.LintPreset) || cfg.LintPreset == "none" {
+		return "", fmt.Errorf("unsupported lint preset %q", cfg.LintPreset)
+	}
+	return render("lint/"+cfg.LintPreset+".yml.tmpl", cfg)
+}
 
-help:
-	@echo "Available targets:"
-	@grep -E '^##' $(MAKEFILE_LIST) | sed 's/##//'`
+// GetGitignoreTemplate renders .gitignore from the features, editor and
+// vendoring choice in cfg.
+func (tg *TemplateGenerator) GetGitignoreTemplate(cfg *config.Config) (string, error) {
+	return render("git/gitignore.tmpl", gitignoreData{Config: cfg, BinDir: BinDir, CoverageFiles: CoverageFiles})
 }
 
-func (tg *TemplateGenerator) GetGitignoreTemplate() string {
-	return `*.exe
-*.exe~
-*.dll
-*.so
-*.dylib
-bin/
-*.test
-*.out
-coverage.html
-go.work
-vendor/
-go.sum
-.vscode/
-.idea/
-*.swp
-*.swo
-*~
-.DS_Store
-.DS_Store?
-._*
-.Spotlight-V100
-.Trashes
-ehthumbs.db
-Thumbs.db
-*.log
-air.log
-.env
-.env.local
-.env.*.local
-tmp/`
+// GetGitattributesTemplate renders .gitattributes, which normalises line
+// endings and marks generated and vendored code.
+func (tg *TemplateGenerator) GetGitattributesTemplate(cfg *config.Config) (string, error) {
+	return render("git/gitattributes.tmpl", cfg)
 }
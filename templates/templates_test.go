@@ -0,0 +1,87 @@
+package templates
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/gostart/config"
+)
+
+// frameworkImports is the import path that shows a rendered main.go was
+// built for the framework rather than falling back to another.
+var frameworkImports = map[string]string{
+	"fiber":   "github.com/gofiber/fiber/v2",
+	"gin":     "github.com/gin-gonic/gin",
+	"echo":    "github.com/labstack/echo/v4",
+	"chi":     "github.com/go-chi/chi/v5",
+	"gorilla": "github.com/gorilla/mux",
+	"stdlib":  "net/http",
+}
+
+// webFiles renders every framework-specific file of a web project.
+func webFiles(t *testing.T, cfg *config.Config) map[string]string {
+	t.Helper()
+	tg := &TemplateGenerator{}
+	main, err := tg.GetWebMainTemplate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{"cmd/main.go": main}
+	for _, get := range []func(*config.Config) (map[string]string, error){
+		tg.GetRouterTemplates,
+		tg.GetLoggerTemplates,
+		tg.GetExampleResourceTemplates,
+	} {
+		rendered, err := get(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range rendered {
+			files[name] = content
+		}
+	}
+	return files
+}
+
+func TestWebFrameworks(t *testing.T) {
+	for _, framework := range WebFrameworks {
+		t.Run(framework, func(t *testing.T) {
+			cfg := &config.Config{ModuleName: "example.com/acme/api", AppType: "web", Framework: framework}
+			files := webFiles(t, cfg)
+			for name, content := range files {
+				if !strings.HasSuffix(name, ".go") {
+					continue
+				}
+				if _, err := format.Source([]byte(content)); err != nil {
+					t.Errorf("%s does not parse: %v\n%s", name, err, content)
+				}
+			}
+			if want := frameworkImports[framework]; !strings.Contains(files["internal/handler/router.go"], `"`+want+`"`) {
+				t.Errorf("router.go does not import %s", want)
+			}
+			if framework != "echo" && strings.Contains(files["cmd/main.go"], "labstack/echo") {
+				t.Error("main.go fell back to echo")
+			}
+		})
+	}
+}
+
+func TestUnknownWebFramework(t *testing.T) {
+	cfg := &config.Config{ModuleName: "example.com/acme/api", AppType: "web", Framework: "martini"}
+	tg := &TemplateGenerator{}
+	want := `unknown web framework "martini"`
+
+	if _, err := tg.GetWebMainTemplate(cfg); err == nil || err.Error() != want {
+		t.Errorf("GetWebMainTemplate error = %v, want %q", err, want)
+	}
+	for name, get := range map[string]func(*config.Config) (map[string]string, error){
+		"GetRouterTemplates":          tg.GetRouterTemplates,
+		"GetLoggerTemplates":          tg.GetLoggerTemplates,
+		"GetExampleResourceTemplates": tg.GetExampleResourceTemplates,
+	} {
+		if _, err := get(cfg); err == nil || err.Error() != want {
+			t.Errorf("%s error = %v, want %q", name, err, want)
+		}
+	}
+}
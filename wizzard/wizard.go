@@ -4,11 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"github.com/SwanHtetAungPhyo/gostart/config"
+	"github.com/SwanHtetAungPhyo/gostart/templatepack"
+	"github.com/SwanHtetAungPhyo/gostart/templates"
 	"github.com/fatih/color"
 	"github.com/manifoldco/promptui"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -48,7 +52,11 @@ func showColorfulBanner() {
 	color.Red("Reminder: Please make sure u do not have the same named folder as the module path you declared \n . I let the generator to make the folder with same name in the go mod path name. ")
 }
 
-type Wizard struct{}
+type Wizard struct {
+	// OpenAPISpec, when set, makes the project a web service generated
+	// from that document instead of asking for the app type.
+	OpenAPISpec string
+}
 
 func NewWizard() *Wizard {
 	return &Wizard{}
@@ -61,19 +69,78 @@ func (w *Wizard) Run() (*config.Config, error) {
 		return nil, err
 	}
 
-	if err := w.getAppType(configuartion); err != nil {
+	if w.OpenAPISpec != "" {
+		configuartion.AppType = "web"
+		configuartion.OpenAPISpec = w.OpenAPISpec
+	} else if err := w.getAppType(configuartion); err != nil {
 		return nil, err
 	}
 
-	if configuartion.AppType == "web" {
+	switch {
+	case configuartion.OpenAPISpec != "":
+		if err := w.getFramework(configuartion); err != nil {
+			return nil, err
+		}
+	case configuartion.AppType == "web":
+		if err := w.getFramework(configuartion); err != nil {
+			return nil, err
+		}
+		configuartion.ExampleResource = w.yesNo("Generate an example resource (model, repository, service, handler)?")
+		configuartion.UseSwaggo = w.yesNo("Generate the OpenAPI spec from swaggo annotations instead of api/openapi.yaml?")
+	case configuartion.AppType == "graphql":
 		if err := w.getFramework(configuartion); err != nil {
 			return nil, err
 		}
+	case configuartion.AppType == "worker":
+		configuartion.UseScheduler = w.yesNo("Include a cron-style scheduler?")
+	}
+
+	if configuartion.IsService() {
+		if err := w.getLogger(configuartion); err != nil {
+			return nil, err
+		}
+	}
+
+	if !configuartion.IsLibrary() {
+		configuartion.UseDocker = w.yesNo("Will you use Docker?")
+		if configuartion.UseDocker {
+			if err := w.getDockerRuntime(configuartion); err != nil {
+				return nil, err
+			}
+		}
+		if configuartion.UseDocker && configuartion.IsService() {
+			configuartion.UseCompose = w.yesNo("Generate compose.yaml for the app and its backing services?")
+		}
+		configuartion.UseAir = w.yesNo("Include air.toml (hot reload)?")
+	}
+	if configuartion.HasHealthEndpoint() {
+		if err := w.getDeploy(configuartion); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.getTaskRunner(configuartion); err != nil {
+		return nil, err
+	}
+	if err := w.getLintPreset(configuartion); err != nil {
+		return nil, err
+	}
+	if err := w.getCI(configuartion); err != nil {
+		return nil, err
+	}
+	if configuartion.UseDocker || configuartion.CI != "none" {
+		if err := w.getGoVersion(configuartion); err != nil {
+			return nil, err
+		}
 	}
 
-	configuartion.UseDocker = w.yesNo("Will you use Docker?")
-	configuartion.UseAir = w.yesNo("Include air.toml (hot reload)?")
-	configuartion.UseMakefile = w.yesNo("Include Makefile?")
+	if err := w.getEditor(configuartion); err != nil {
+		return nil, err
+	}
+	configuartion.UseVendor = w.yesNo("Vendor dependencies into vendor/ and commit them?")
+	configuartion.UseGitattributes = w.yesNo("Add a .gitattributes (LF line endings, generated code marked)?")
+	if err := w.getLicense(configuartion); err != nil {
+		return nil, err
+	}
 
 	configuartion.ProjectDir = filepath.Base(configuartion.ModuleName)
 	if err := w.getDependencies(configuartion); err != nil {
@@ -83,6 +150,119 @@ func (w *Wizard) Run() (*config.Config, error) {
 	return configuartion, nil
 }
 
+func (w *Wizard) RunTemplate(pack *templatepack.Pack) (*config.Config, error) {
+	configuartion := &config.Config{
+		Template:    pack.Source,
+		TemplateDir: pack.Dir,
+	}
+
+	if err := w.getModuleName(configuartion); err != nil {
+		return nil, err
+	}
+	color.Cyan("📦 Using template %s (%s)", pack.Name(), shortCommit(pack.Commit))
+
+	configuartion.ProjectDir = filepath.Base(configuartion.ModuleName)
+	if err := w.getTemplateAnswers(configuartion, pack); err != nil {
+		return nil, err
+	}
+	if err := w.getLicense(configuartion); err != nil {
+		return nil, err
+	}
+	if err := w.getDependencies(configuartion); err != nil {
+		return nil, err
+	}
+
+	return configuartion, nil
+}
+
+func shortCommit(commit string) string {
+	if commit == "" {
+		return "local"
+	}
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
+
+func (w *Wizard) getTemplateAnswers(config *config.Config, pack *templatepack.Pack) error {
+	data := templatepack.BaseData(config.ModuleName, config.ProjectDir)
+	answers := make(map[string]any)
+
+	for i := range pack.Manifest.Questions {
+		question := &pack.Manifest.Questions[i]
+
+		enabled, err := question.Enabled(data)
+		if err != nil {
+			return fmt.Errorf("question %q: %w", question.Name, err)
+		}
+		var answer any
+		if enabled {
+			answer, err = w.askTemplateQuestion(question, data)
+		} else {
+			answer, err = question.DefaultValue(data)
+		}
+		if err != nil {
+			return fmt.Errorf("question %q: %w", question.Name, err)
+		}
+
+		data[question.Name] = answer
+		answers[question.Name] = answer
+	}
+
+	config.TemplateVars = answers
+	return nil
+}
+
+func (w *Wizard) askTemplateQuestion(question *templatepack.Question, data map[string]any) (any, error) {
+	defaultValue, err := question.DefaultString(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch question.Type {
+	case templatepack.QuestionBool, templatepack.QuestionChoice:
+		items := question.Choices
+		if question.Type == templatepack.QuestionBool {
+			items = []string{"true", "false"}
+			if defaultValue == "" {
+				defaultValue = "false"
+			}
+		}
+		cursor := 0
+		for i, item := range items {
+			if item == defaultValue {
+				cursor = i
+			}
+		}
+
+		sel := promptui.Select{
+			Label:     question.Prompt,
+			Items:     items,
+			CursorPos: cursor,
+		}
+		_, result, err := sel.Run()
+		if err != nil {
+			return nil, err
+		}
+		return question.Parse(result)
+	}
+
+	prompt := promptui.Prompt{
+		Label:   question.Prompt,
+		Default: defaultValue,
+		Validate: func(input string) error {
+			_, err := question.Parse(input)
+			return err
+		},
+	}
+	result, err := prompt.Run()
+	if err != nil {
+		return nil, err
+	}
+	return question.Parse(result)
+}
+
 func (w *Wizard) getModuleName(config *config.Config) error {
 	showColorfulBanner()
 	prompt := promptui.Prompt{
@@ -105,7 +285,7 @@ func (w *Wizard) getModuleName(config *config.Config) error {
 }
 
 func (w *Wizard) getAppType(config *config.Config) error {
-	appTypes := []string{"cli", "cobra", "web"}
+	appTypes := []string{"cli", "cobra", "web", "graphql", "grpc", "worker", "library"}
 	sel := promptui.Select{
 		Label: "What type of application?",
 		Items: appTypes,
@@ -120,10 +300,9 @@ func (w *Wizard) getAppType(config *config.Config) error {
 }
 
 func (w *Wizard) getFramework(config *config.Config) error {
-	frameworks := []string{"fiber", "gin", "echo"}
 	sel := promptui.Select{
 		Label: "Choose web framework",
-		Items: frameworks,
+		Items: templates.WebFrameworks,
 	}
 
 	_, result, err := sel.Run()
@@ -134,6 +313,207 @@ func (w *Wizard) getFramework(config *config.Config) error {
 	return nil
 }
 
+func (w *Wizard) getLogger(config *config.Config) error {
+	sel := promptui.Select{
+		Label: "Choose logging library",
+		Items: templates.Loggers,
+	}
+
+	_, result, err := sel.Run()
+	if err != nil {
+		return err
+	}
+	config.Logger = result
+	return nil
+}
+
+func (w *Wizard) getTaskRunner(config *config.Config) error {
+	sel := promptui.Select{
+		Label: "Task runner (make, Task or just)",
+		Items: templates.TaskRunners,
+	}
+
+	_, result, err := sel.Run()
+	if err != nil {
+		return err
+	}
+	config.TaskRunner = result
+	return nil
+}
+
+func (w *Wizard) getLintPreset(config *config.Config) error {
+	sel := promptui.Select{
+		Label: "golangci-lint preset",
+		Items: templates.LintPresets,
+	}
+
+	_, result, err := sel.Run()
+	if err != nil {
+		return err
+	}
+	config.LintPreset = result
+	return nil
+}
+
+func (w *Wizard) getDockerRuntime(config *config.Config) error {
+	sel := promptui.Select{
+		Label: "Docker runtime image",
+		Items: templates.DockerRuntimes,
+	}
+
+	_, result, err := sel.Run()
+	if err != nil {
+		return err
+	}
+	config.DockerRuntime = result
+	return nil
+}
+
+func (w *Wizard) getDeploy(config *config.Config) error {
+	sel := promptui.Select{
+		Label: "Kubernetes manifests in deploy/ (plain or Helm)",
+		Items: templates.DeployTargets,
+	}
+
+	_, result, err := sel.Run()
+	if err != nil {
+		return err
+	}
+	config.Deploy = result
+	return nil
+}
+
+// getEditor asks which editor's workspace files .gitignore should cover.
+func (w *Wizard) getEditor(config *config.Config) error {
+	sel := promptui.Select{
+		Label: "Editor (for .gitignore)",
+		Items: templates.Editors,
+	}
+
+	_, result, err := sel.Run()
+	if err != nil {
+		return err
+	}
+	config.Editor = result
+	return nil
+}
+
+// getLicense asks for the license and, unless there is none, its copyright
+// holder and year and whether Go files get SPDX headers.
+func (w *Wizard) getLicense(config *config.Config) error {
+	sel := promptui.Select{
+		Label: "License (SPDX identifier)",
+		Items: templates.Licenses,
+	}
+	_, result, err := sel.Run()
+	if err != nil {
+		return err
+	}
+	config.License = result
+	if !config.UsesLicense() {
+		return nil
+	}
+
+	author := promptui.Prompt{
+		Label:   "Copyright holder",
+		Default: gitUserName(),
+		Validate: func(input string) error {
+			if strings.TrimSpace(input) == "" {
+				return fmt.Errorf("copyright holder cannot be empty")
+			}
+			return nil
+		},
+	}
+	if result, err = author.Run(); err != nil {
+		return err
+	}
+	config.LicenseAuthor = strings.TrimSpace(result)
+
+	year := promptui.Prompt{
+		Label:   "Copyright year",
+		Default: strconv.Itoa(time.Now().Year()),
+		Validate: func(input string) error {
+			if !yearPattern.MatchString(strings.TrimSpace(input)) {
+				return fmt.Errorf("expected a year like 2025 or a range like 2020-2025")
+			}
+			return nil
+		},
+	}
+	if result, err = year.Run(); err != nil {
+		return err
+	}
+	config.LicenseYear = strings.TrimSpace(result)
+
+	config.SPDXHeaders = w.yesNo("Add SPDX license headers to generated Go files?")
+	return nil
+}
+
+// gitUserName suggests the author configured for git, if any.
+func gitUserName() string {
+	out, err := exec.Command("git", "config", "user.name").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func (w *Wizard) getCI(config *config.Config) error {
+	sel := promptui.Select{
+		Label: "Generate a CI pipeline?",
+		Items: templates.CIProviders,
+	}
+
+	_, result, err := sel.Run()
+	if err != nil {
+		return err
+	}
+	config.CI = result
+	return nil
+}
+
+// getGoVersion asks for the toolchain that CI jobs and the Docker builder
+// image use.
+func (w *Wizard) getGoVersion(config *config.Config) error {
+	prompt := promptui.Prompt{
+		Label:   "Go version for CI and Docker builds",
+		Default: localGoVersion(),
+		Validate: func(input string) error {
+			match := goVersionPattern.FindStringSubmatch(strings.TrimSpace(input))
+			if match == nil {
+				return fmt.Errorf("expected a version like 1.24 or 1.24.3")
+			}
+			// Pipelines run tools through go tool, added in Go 1.24.
+			if minor, _ := strconv.Atoi(match[1]); minor < 24 {
+				return fmt.Errorf("Go 1.24 or newer is required")
+			}
+			return nil
+		},
+	}
+	version, err := prompt.Run()
+	if err != nil {
+		return err
+	}
+	config.GoVersion = strings.TrimSpace(version)
+	return nil
+}
+
+var (
+	goVersionPattern = regexp.MustCompile(`^1\.(\d+)(\.\d+)?$`)
+	toolchainPattern = regexp.MustCompile(`^go(1\.\d+)`)
+	yearPattern      = regexp.MustCompile(`^\d{4}(-\d{4})?$`)
+)
+
+// localGoVersion is the major.minor of the go command on PATH.
+func localGoVersion() string {
+	out, err := exec.Command("go", "env", "GOVERSION").Output()
+	if err == nil {
+		if match := toolchainPattern.FindStringSubmatch(strings.TrimSpace(string(out))); match != nil {
+			return match[1]
+		}
+	}
+	return "1.24"
+}
+
 func (w *Wizard) yesNo(label string) bool {
 	sel := promptui.Select{
 		Label: label,
@@ -188,6 +568,8 @@ func (w *Wizard) getCuratedCategories() map[string][]Dependency {
 			{Name: "SQLite Driver", ImportPath: "gorm.io/driver/sqlite", URL: "https://github.com/go-gorm/sqlite"},
 			{Name: "MongoDB Driver", ImportPath: "go.mongodb.org/mongo-driver/mongo", URL: "https://github.com/mongodb/mongo-go-driver"},
 			{Name: "Redis", ImportPath: "github.com/redis/go-redis/v9", URL: "https://github.com/redis/go-redis"},
+			{Name: "golang-migrate", ImportPath: "github.com/golang-migrate/migrate/v4", URL: "https://github.com/golang-migrate/migrate"},
+			{Name: "sqlc", ImportPath: "github.com/sqlc-dev/sqlc", URL: "https://github.com/sqlc-dev/sqlc"},
 		},
 		"⚙️ CLI Tools": {
 			{Name: "Cobra", ImportPath: "github.com/spf13/cobra", URL: "https://github.com/spf13/cobra"},